@@ -0,0 +1,163 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 错误分类，可配合 errors.Is 判断 APIError 的类型
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrSignatureInvalid    = errors.New("signature invalid")
+)
+
+var insufficientBalanceCodes = map[string]bool{
+	"account-balance-insufficient-error":        true,
+	"account-frozen-balance-insufficient-error": true,
+	"insufficient-balance":                      true,
+	"order-accountbalance-error":                true,
+	"insufficient-exchange-fund":                true,
+}
+
+var rateLimitedCodes = map[string]bool{
+	"api-request-too-frequent":             true,
+	"too-many-requests":                    true,
+	"base-request-exceed-frequency-limit":  true,
+	"order-request-exceed-frequency-limit": true,
+	"api-request-exceed-frequency-limit":   true,
+}
+
+var orderNotFoundCodes = map[string]bool{
+	"base-record-invalid":  true,
+	"order-not-found":      true,
+	"order-not-exist":      true,
+	"base-order-not-exist": true,
+}
+
+var signatureInvalidCodes = map[string]bool{
+	"api-signature-not-valid":    true,
+	"api-signature-check-failed": true,
+	"api-key-invalid":            true,
+	"invalid-access-key-id":      true,
+}
+
+// Source 错误来源接口，不同接口的数字错误码含义不同
+type Source string
+
+const (
+	SourceREST             Source = "rest"               // REST 接口
+	SourceWebSocket        Source = "websocket"          // WebSocket 行情接口
+	SourceWebSocketTrade   Source = "websocket-trade"    // WebSocket v1 资产&订单接口
+	SourceWebSocketTradeV2 Source = "websocket-trade-v2" // WebSocket v2 资产&订单接口
+)
+
+// authEndpoint WebSocket 交易接口鉴权请求的 topic/ch，鉴权失败均按签名校验失败处理
+const authEndpoint = "auth"
+
+// intCodes 按接口区分的数字错误码（v2 接口的 code、WebSocket v1 交易接口的 err-code）
+var intCodes = map[Source]map[error]map[int]bool{
+	SourceREST: {
+		ErrSignatureInvalid: {1002: true, 1003: true}, // unauthorized、invalid signature
+		ErrRateLimited:      {http.StatusTooManyRequests: true},
+	},
+	SourceWebSocketTrade: {
+		ErrRateLimited: {http.StatusTooManyRequests: true},
+	},
+	SourceWebSocketTradeV2: {
+		ErrSignatureInvalid: {2002: true}, // invalid.auth.state
+		ErrRateLimited:      {http.StatusTooManyRequests: true, 4000: true},
+	},
+}
+
+// APIError 火币接口返回的错误
+type APIError struct {
+	Source     Source // 错误来源接口，为空时按 REST 接口处理
+	ErrCode    string // v1 接口的 err-code
+	Code       int    // v2 接口的 code，WebSocket v1 交易接口的 err-code
+	Message    string // err-msg 或 message
+	HTTPStatus int    // REST 接口的HTTP状态码，WebSocket 接口为0
+	Endpoint   string // REST 接口的path，WebSocket 接口的topic
+	Body       []byte // 原始响应
+}
+
+func (e *APIError) Error() string {
+	code := e.ErrCode
+	if code == "" && e.Code != 0 {
+		code = fmt.Sprint(e.Code)
+	}
+	if code == "" && e.HTTPStatus >= http.StatusBadRequest {
+		code = fmt.Sprint(e.HTTPStatus)
+	}
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.HTTPStatus)
+	}
+	if code == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (%s)", message, code)
+}
+
+// Is 支持 errors.Is(err, ErrRateLimited) 等形式的错误分类判断
+func (e *APIError) Is(target error) bool {
+	if e.Code != 0 && e.intCodeIs(target) {
+		return true
+	}
+	switch target {
+	case ErrInsufficientBalance:
+		return insufficientBalanceCodes[e.ErrCode]
+	case ErrRateLimited:
+		return rateLimitedCodes[e.ErrCode] || e.HTTPStatus == http.StatusTooManyRequests
+	case ErrOrderNotFound:
+		return orderNotFoundCodes[e.ErrCode]
+	case ErrSignatureInvalid:
+		return signatureInvalidCodes[e.ErrCode] || e.isAuthFailure()
+	}
+	return false
+}
+
+// intCodeIs 按错误来源接口的数字错误码分类
+func (e *APIError) intCodeIs(target error) bool {
+	source := e.Source
+	if source == "" {
+		source = SourceREST
+	}
+	return intCodes[source][target][e.Code]
+}
+
+// isAuthFailure 是否为 WebSocket 交易接口的鉴权失败
+func (e *APIError) isAuthFailure() bool {
+	return (e.Source == SourceWebSocketTrade || e.Source == SourceWebSocketTradeV2) && e.Endpoint == authEndpoint
+}
+
+// IsInsufficientBalance 是否为余额不足错误
+func IsInsufficientBalance(err error) bool {
+	return errors.Is(err, ErrInsufficientBalance)
+}
+
+// IsRateLimited 是否为请求频率超限错误
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsOrderNotFound 是否为订单不存在错误
+func IsOrderNotFound(err error) bool {
+	return errors.Is(err, ErrOrderNotFound)
+}
+
+// IsSignatureInvalid 是否为签名校验失败错误
+func IsSignatureInvalid(err error) bool {
+	return errors.Is(err, ErrSignatureInvalid)
+}
+
+// AsAPIError 从错误链中取出 APIError
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
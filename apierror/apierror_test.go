@@ -0,0 +1,67 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *APIError
+		target error
+		want   bool
+	}{
+		{"v1 balance", &APIError{ErrCode: "account-balance-insufficient-error"}, ErrInsufficientBalance, true},
+		{"v1 rate limited", &APIError{ErrCode: "api-request-too-frequent"}, ErrRateLimited, true},
+		{"http 429", &APIError{HTTPStatus: http.StatusTooManyRequests}, ErrRateLimited, true},
+		{"v1 not found", &APIError{ErrCode: "base-record-invalid"}, ErrOrderNotFound, true},
+		{"v1 signature", &APIError{ErrCode: "api-signature-not-valid"}, ErrSignatureInvalid, true},
+		{"rest 1003", &APIError{Code: 1003}, ErrSignatureInvalid, true},
+		{"rest 429 code", &APIError{Source: SourceREST, Code: 429}, ErrRateLimited, true},
+		{"ws v2 2002", &APIError{Source: SourceWebSocketTradeV2, Code: 2002}, ErrSignatureInvalid, true},
+		{"ws v2 4000", &APIError{Source: SourceWebSocketTradeV2, Code: 4000}, ErrRateLimited, true},
+		{"ws v2 1003", &APIError{Source: SourceWebSocketTradeV2, Code: 1003}, ErrSignatureInvalid, false},
+		{"rest 2002", &APIError{Code: 2002}, ErrSignatureInvalid, false},
+		{"ws v1 auth", &APIError{Source: SourceWebSocketTrade, Code: 2001, Endpoint: "auth"}, ErrSignatureInvalid, true},
+		{"rest auth path", &APIError{Code: 2001, Endpoint: "auth"}, ErrSignatureInvalid, false},
+		{"unrelated", &APIError{ErrCode: "order-limitorder-amount-min-error"}, ErrRateLimited, false},
+	}
+	for _, test := range tests {
+		if got := errors.Is(test.err, test.target); got != test.want {
+			t.Errorf("%s: errors.Is(%v) = %v, want %v", test.name, test.target, got, test.want)
+		}
+	}
+}
+
+func TestWrapped(t *testing.T) {
+	err := fmt.Errorf("place order: %w", &APIError{ErrCode: "insufficient-balance", Message: "balance"})
+	if !IsInsufficientBalance(err) {
+		t.Error("IsInsufficientBalance(wrapped) = false")
+	}
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.ErrCode != "insufficient-balance" {
+		t.Errorf("AsAPIError = %v, %v", apiErr, ok)
+	}
+	if _, ok := AsAPIError(errors.New("network")); ok {
+		t.Error("AsAPIError(non APIError) = true")
+	}
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		err  *APIError
+		want string
+	}{
+		{&APIError{ErrCode: "base-record-invalid", Message: "record invalid"}, "record invalid (base-record-invalid)"},
+		{&APIError{Code: 2002, Message: "invalid.auth.state"}, "invalid.auth.state (2002)"},
+		{&APIError{HTTPStatus: http.StatusBadGateway}, "Bad Gateway (502)"},
+	}
+	for _, test := range tests {
+		if got := test.err.Error(); got != test.want {
+			t.Errorf("Error() = %q, want %q", got, test.want)
+		}
+	}
+}
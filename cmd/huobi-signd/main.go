@@ -0,0 +1,57 @@
+// huobi-signd 参考签名服务，在 Unix socket 上为交易服务提供签名。
+//
+//	HUOBI_ACCESS_KEY=xxx HUOBI_SECRET_KEY=xxx huobi-signd -socket /run/huobi-signd.sock
+//	HUOBI_ACCESS_KEY=xxx huobi-signd -socket /run/huobi-signd.sock -ed25519 key.pem
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/feeeei/huobiapi-go/sign"
+	"github.com/feeeei/huobiapi-go/sign/signd"
+)
+
+func main() {
+	socketPath := flag.String("socket", "/tmp/huobi-signd.sock", "Unix socket 路径")
+	ed25519Key := flag.String("ed25519", "", "Ed25519 PEM 私钥文件，不指定时使用 HUOBI_SECRET_KEY 进行 HmacSHA256 签名")
+	flag.Parse()
+
+	accessKeyID := os.Getenv("HUOBI_ACCESS_KEY")
+	if accessKeyID == "" {
+		log.Fatalln("HUOBI_ACCESS_KEY is required")
+	}
+
+	var signer sign.Signer
+	if *ed25519Key != "" {
+		s, err := sign.LoadEd25519SignerFile(*ed25519Key)
+		if err != nil {
+			log.Fatalln("Load Ed25519 key error:", err)
+		}
+		signer = s
+	} else {
+		secret := os.Getenv("HUOBI_SECRET_KEY")
+		if secret == "" {
+			log.Fatalln("HUOBI_SECRET_KEY is required")
+		}
+		signer = sign.NewHmacSigner(secret)
+	}
+	// 读取后从环境变量中移除，避免被子进程继承
+	os.Unsetenv("HUOBI_SECRET_KEY")
+
+	server := signd.NewServer(accessKeyID, signer)
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		server.Close()
+	}()
+
+	log.Println("huobi-signd listening on", *socketPath)
+	if err := server.ListenAndServe(*socketPath); err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -1,8 +1,17 @@
 package config
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/feeeei/huobiapi-go/debug"
+	"github.com/feeeei/huobiapi-go/sign"
+	"github.com/gorilla/websocket"
 )
 
 var HuobiApiHost string
@@ -13,10 +22,328 @@ var HuobiWsTradeV2Endpoint *url.URL
 
 var HeartbeatDuration = time.Second * 5
 
+// RequestTimeout WebSocket 订阅、请求及鉴权等待响应的默认超时时间
+var RequestTimeout = time.Second * 10
+
+// DefaultUserAgent 默认的请求 User-Agent
+const DefaultUserAgent = "Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/39.0.2171.71 Safari/537.36"
+
 func SetAPIHost(host string) {
 	HuobiApiHost = host
-	HuobiRestEndpoint, _ = url.Parse("https://" + host)
-	HuobiWsEndpoint, _ = url.Parse("wss://" + host + "/ws")
-	HuobiWsTradeEndpoint, _ = url.Parse("wss://" + host + "/ws/v1")
-	HuobiWsTradeV2Endpoint, _ = url.Parse("wss://" + host + "/ws/v2")
+	HuobiRestEndpoint, HuobiWsEndpoint, HuobiWsTradeEndpoint, HuobiWsTradeV2Endpoint = endpoints(host)
+}
+
+func endpoints(host string) (rest, ws, wsTrade, wsTradeV2 *url.URL) {
+	rest, _ = url.Parse("https://" + host)
+	ws, _ = url.Parse("wss://" + host + "/ws")
+	wsTrade, _ = url.Parse("wss://" + host + "/ws/v1")
+	wsTradeV2, _ = url.Parse("wss://" + host + "/ws/v2")
+	return
+}
+
+// Config 单个Client的配置，未指定的项使用创建Client时的全局配置
+type Config struct {
+	Host              string
+	RestEndpoint      *url.URL
+	WsEndpoint        *url.URL
+	WsTradeEndpoint   *url.URL
+	WsTradeV2Endpoint *url.URL
+	HeartbeatDuration time.Duration
+	RequestTimeout    time.Duration // WebSocket 等待响应的超时时间，为0时不超时
+	HTTPClient        *http.Client
+	UserAgent         string
+	Dialer            *websocket.Dialer
+	Proxy             *url.URL        // REST请求及WebSocket连接使用的代理，在所有配置项生效后应用
+	RateLimiter       RateLimiter     // 为nil时不做客户端限频
+	Retry             *RetryPolicy    // 为nil时不重试
+	Clock             sign.Clock      // 签名时间来源，为nil时使用本地时间
+	Signer            sign.Signer     // 签名算法，为nil时使用 AccessKeySecret 进行 HmacSHA256 签名
+	Logger            *debug.Logger   // 为nil时跟随全局 Debug 设置
+	Hooks             Hooks           // WebSocket 连接状态事件回调
+	Reconnect         ReconnectPolicy // WebSocket 断线重连策略
+	httpProxyErr      error
+}
+
+// ErrProxyNotApplied HTTPClient 使用自定义 RoundTripper 时无法应用 WithProxy，需在 RoundTripper 中自行设置代理
+var ErrProxyNotApplied = errors.New("proxy can not be applied to custom RoundTripper")
+
+// RateLimiter REST请求限频器，多个Client共用同一UID时可共享同一个限频器
+type RateLimiter interface {
+	// Wait 在发送请求前调用，返回错误时放弃该请求
+	Wait(ctx context.Context, method, path string) error
+	// Update 在收到响应后调用，用于根据响应头实时调整限频状态
+	Update(method, path string, header http.Header)
+}
+
+// Hooks WebSocket 连接状态事件回调，未设置的回调不触发。
+// 回调按事件发生顺序在独立的goroutine中依次执行，可以在回调中调用 Close 或 Reconnect，
+// 单个回调长时间阻塞会推迟后续回调
+type Hooks struct {
+	OnConnected         func()                        // 连接建立（含每次重连），交易Client在鉴权成功后触发
+	OnDisconnected      func(err error)               // 连接断开，主动关闭时 err 为 ErrClientClosed
+	OnReconnecting      func(attempt int)             // 开始第 attempt 次重连
+	OnReconnected       func()                        // 重连、鉴权及恢复订阅完成
+	OnAuthFailed        func(err error)               // 交易Client鉴权失败
+	OnResubscribeFailed func(topic string, err error) // 重连后恢复订阅失败
+	OnReconnectGaveUp   func(err error)               // 按重连策略放弃重连
+}
+
+// RetryPolicy REST请求重试策略，等待时间按指数增长并加入随机抖动
+type RetryPolicy struct {
+	MaxAttempts int           // 最大尝试次数（含首次请求），小于等于1时不重试
+	BaseDelay   time.Duration // 首次重试前的等待时间
+	MaxDelay    time.Duration // 单次等待时间上限，为0时不设上限
+	Jitter      float64       // 随机抖动比例，取值 [0, 1]
+}
+
+// DefaultRetryPolicy 默认重试策略
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    3 * time.Second,
+	Jitter:      0.2,
+}
+
+// Backoff 返回第 attempt 次请求失败后、下次重试前的等待时间
+func (policy *RetryPolicy) Backoff(attempt int) time.Duration {
+	return backoff(policy.BaseDelay, policy.MaxDelay, policy.Jitter, attempt)
+}
+
+// CanRetry 第 attempt 次请求失败后是否还可以重试
+func (policy *RetryPolicy) CanRetry(attempt int) bool {
+	return policy != nil && attempt < policy.MaxAttempts
+}
+
+// ReconnectPolicy WebSocket 断线重连策略
+type ReconnectPolicy interface {
+	// NextDelay 返回第 attempt 次重连前的等待时间，elapsed 为本次断线后已经过的时间，返回false时放弃重连
+	NextDelay(attempt int, elapsed time.Duration) (time.Duration, bool)
+}
+
+// ReconnectBackoff 指数退避重连策略，等待时间按指数增长并加入随机抖动
+type ReconnectBackoff struct {
+	BaseDelay   time.Duration // 首次重连前的等待时间
+	MaxDelay    time.Duration // 单次等待时间上限，为0时不设上限
+	Jitter      float64       // 随机抖动比例，取值 [0, 1]
+	MaxAttempts int           // 最大重连次数，为0时不限
+	MaxElapsed  time.Duration // 单次断线后的最长重连时间，为0时不限
+}
+
+// DefaultReconnectBackoff 默认重连策略，不会放弃重连
+var DefaultReconnectBackoff = ReconnectBackoff{
+	BaseDelay: time.Second,
+	MaxDelay:  30 * time.Second,
+	Jitter:    0.2,
+}
+
+// NextDelay 超过最大重连次数，或等待后将超过最长重连时间时放弃重连
+func (policy *ReconnectBackoff) NextDelay(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	if policy.MaxAttempts > 0 && attempt > policy.MaxAttempts {
+		return 0, false
+	}
+	delay := backoff(policy.BaseDelay, policy.MaxDelay, policy.Jitter, attempt)
+	if policy.MaxElapsed > 0 && elapsed+delay > policy.MaxElapsed {
+		return 0, false
+	}
+	return delay, true
+}
+
+// backoff 返回第 attempt 次失败后的等待时间
+func backoff(base, max time.Duration, jitter float64, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if max > 0 && delay >= max {
+			delay = max
+			break
+		}
+	}
+	if jitter > 0 {
+		delta := float64(delay) * jitter
+		delay += time.Duration(delta * (2*rand.Float64() - 1))
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
+
+// Option Client配置项
+type Option func(config *Config)
+
+// New 以全局配置为默认值，应用 options 生成Client配置
+func New(options ...Option) *Config {
+	config := &Config{
+		Host:              HuobiApiHost,
+		RestEndpoint:      HuobiRestEndpoint,
+		WsEndpoint:        HuobiWsEndpoint,
+		WsTradeEndpoint:   HuobiWsTradeEndpoint,
+		WsTradeV2Endpoint: HuobiWsTradeV2Endpoint,
+		HeartbeatDuration: HeartbeatDuration,
+		RequestTimeout:    RequestTimeout,
+		HTTPClient:        http.DefaultClient,
+		UserAgent:         DefaultUserAgent,
+		Dialer:            websocket.DefaultDialer,
+		Reconnect:         &DefaultReconnectBackoff,
+	}
+	for _, option := range options {
+		option(config)
+	}
+	config.applyProxy()
+	return config
+}
+
+// NewSign 按配置创建签名
+func (config *Config) NewSign(accessKeyID, accessKeySecret, version string) *sign.Sign {
+	var s *sign.Sign
+	if config.Signer != nil {
+		s = sign.NewSignWithSigner(accessKeyID, config.Signer, version)
+	} else {
+		s = sign.NewSign(accessKeyID, accessKeySecret, version)
+	}
+	s.Clock = config.Clock
+	return s
+}
+
+// WithHost 使用指定Host，同时修改REST与WebSocket地址
+func WithHost(host string) Option {
+	return func(config *Config) {
+		config.Host = host
+		config.RestEndpoint, config.WsEndpoint, config.WsTradeEndpoint, config.WsTradeV2Endpoint = endpoints(host)
+	}
+}
+
+// WithHTTPClient 使用指定的 http.Client 发送REST请求
+func WithHTTPClient(client *http.Client) Option {
+	return func(config *Config) {
+		if client != nil {
+			config.HTTPClient = client
+		}
+	}
+}
+
+// WithHeartbeat 设置WebSocket心跳间隔，不大于0时忽略
+func WithHeartbeat(duration time.Duration) Option {
+	return func(config *Config) {
+		if duration > 0 {
+			config.HeartbeatDuration = duration
+		}
+	}
+}
+
+// WithRequestTimeout 设置WebSocket订阅、请求及鉴权等待响应的超时时间，为0时不超时
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.RequestTimeout = timeout
+	}
+}
+
+// WithDebug 单独设置该Client是否打印调试日志，不受全局 Debug 影响
+func WithDebug(output bool) Option {
+	return func(config *Config) {
+		config.Logger = debug.NewLogger(output)
+	}
+}
+
+// WithUserAgent 设置REST请求及WebSocket握手使用的 User-Agent
+func WithUserAgent(userAgent string) Option {
+	return func(config *Config) {
+		config.UserAgent = userAgent
+	}
+}
+
+// WithDialer 使用指定的 websocket.Dialer 建立WebSocket连接
+func WithDialer(dialer *websocket.Dialer) Option {
+	return func(config *Config) {
+		if dialer != nil {
+			config.Dialer = dialer
+		}
+	}
+}
+
+// WithProxy REST请求与WebSocket连接均通过指定代理，支持 http、https、socks5 代理。
+// 代理在所有配置项生效后应用，与 WithHTTPClient、WithDialer 的先后顺序无关；
+// WithHTTPClient 传入的 Transport 不是 *http.Transport 时无法设置代理，创建REST Client会返回 ErrProxyNotApplied
+func WithProxy(proxyURL *url.URL) Option {
+	return func(config *Config) {
+		config.Proxy = proxyURL
+	}
+}
+
+// applyProxy 基于 HTTPClient 与 Dialer 生成使用代理的副本，不会修改默认或传入的对象。
+// HTTPClient 使用自定义 RoundTripper 时无法设置代理，REST Client 创建时返回 ErrProxyNotApplied
+func (config *Config) applyProxy() {
+	if config.Proxy == nil {
+		return
+	}
+	proxy := http.ProxyURL(config.Proxy)
+
+	var transport *http.Transport
+	switch t := config.HTTPClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		config.httpProxyErr = fmt.Errorf("%w: %T", ErrProxyNotApplied, t)
+	}
+	if transport != nil {
+		transport.Proxy = proxy
+		httpClient := *config.HTTPClient
+		httpClient.Transport = transport
+		config.HTTPClient = &httpClient
+	}
+
+	dialer := *config.Dialer
+	dialer.Proxy = proxy
+	config.Dialer = &dialer
+}
+
+// HTTPProxyErr REST请求无法使用 WithProxy 指定的代理时返回错误
+func (config *Config) HTTPProxyErr() error {
+	return config.httpProxyErr
+}
+
+// WithRateLimiter REST请求使用指定的限频器
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(config *Config) {
+		config.RateLimiter = limiter
+	}
+}
+
+// WithRetry REST请求使用指定的重试策略
+func WithRetry(policy RetryPolicy) Option {
+	return func(config *Config) {
+		config.Retry = &policy
+	}
+}
+
+// WithClock 签名时使用指定的时间来源，如 restclient.ClockSync
+func WithClock(clock sign.Clock) Option {
+	return func(config *Config) {
+		config.Clock = clock
+	}
+}
+
+// WithSigner 使用指定的签名算法，如 Ed25519 私钥签名，此时 AccessKeySecret 可以为空
+func WithSigner(signer sign.Signer) Option {
+	return func(config *Config) {
+		config.Signer = signer
+	}
+}
+
+// WithHooks 设置WebSocket连接状态事件回调
+func WithHooks(hooks Hooks) Option {
+	return func(config *Config) {
+		config.Hooks = hooks
+	}
+}
+
+// WithReconnect 设置WebSocket断线重连策略，如 &ReconnectBackoff{MaxAttempts: 10}
+func WithReconnect(policy ReconnectPolicy) Option {
+	return func(config *Config) {
+		if policy != nil {
+			config.Reconnect = policy
+		}
+	}
 }
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func proxyOf(t *testing.T, proxy func(*http.Request) (*url.URL, error)) string {
+	t.Helper()
+	if proxy == nil {
+		return ""
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.huobi.pro/", nil)
+	u, err := proxy(req)
+	if err != nil || u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+func TestWithProxy(t *testing.T) {
+	proxy, _ := url.Parse("http://127.0.0.1:8080")
+	httpClient := &http.Client{Transport: &http.Transport{}, Timeout: time.Second}
+	dialer := &websocket.Dialer{HandshakeTimeout: time.Second}
+	orders := map[string][]Option{
+		"proxy first": {WithProxy(proxy), WithHTTPClient(httpClient), WithDialer(dialer)},
+		"proxy last":  {WithHTTPClient(httpClient), WithDialer(dialer), WithProxy(proxy)},
+	}
+	for name, options := range orders {
+		config := New(options...)
+		if err := config.HTTPProxyErr(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		transport, ok := config.HTTPClient.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("%s: Transport = %T", name, config.HTTPClient.Transport)
+		}
+		if got := proxyOf(t, transport.Proxy); got != proxy.String() {
+			t.Errorf("%s: HTTP proxy = %q", name, got)
+		}
+		if got := proxyOf(t, config.Dialer.Proxy); got != proxy.String() {
+			t.Errorf("%s: dialer proxy = %q", name, got)
+		}
+		if config.HTTPClient.Timeout != time.Second || config.Dialer.HandshakeTimeout != time.Second {
+			t.Errorf("%s: custom settings lost", name)
+		}
+	}
+	if httpClient.Transport.(*http.Transport).Proxy != nil || dialer.Proxy != nil {
+		t.Error("options passed in were modified")
+	}
+	New(WithProxy(proxy))
+	if proxyOf(t, websocket.DefaultDialer.Proxy) == proxy.String() {
+		t.Error("default dialer was modified")
+	}
+}
+
+func TestWithProxyCustomRoundTripper(t *testing.T) {
+	proxy, _ := url.Parse("http://127.0.0.1:8080")
+	custom := roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, errors.New("unused") })
+	config := New(WithHTTPClient(&http.Client{Transport: custom}), WithProxy(proxy))
+	if err := config.HTTPProxyErr(); !errors.Is(err, ErrProxyNotApplied) {
+		t.Errorf("HTTPProxyErr = %v, want ErrProxyNotApplied", err)
+	}
+	if got := proxyOf(t, config.Dialer.Proxy); got != proxy.String() {
+		t.Errorf("dialer proxy = %q", got)
+	}
+	if err := New(WithHTTPClient(&http.Client{Transport: custom})).HTTPProxyErr(); err != nil {
+		t.Errorf("without proxy: HTTPProxyErr = %v", err)
+	}
+}
+
+func TestWithHeartbeat(t *testing.T) {
+	if got := New(WithHeartbeat(0)).HeartbeatDuration; got != HeartbeatDuration {
+		t.Errorf("WithHeartbeat(0) = %v, want default", got)
+	}
+	if got := New(WithHeartbeat(-time.Second)).HeartbeatDuration; got != HeartbeatDuration {
+		t.Errorf("WithHeartbeat(-1s) = %v, want default", got)
+	}
+	if got := New(WithHeartbeat(time.Second)).HeartbeatDuration; got != time.Second {
+		t.Errorf("WithHeartbeat(1s) = %v", got)
+	}
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	policy := &ReconnectBackoff{BaseDelay: time.Second, MaxDelay: 4 * time.Second, MaxAttempts: 3}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second} {
+		if delay, ok := policy.NextDelay(attempt, 0); !ok || delay != want {
+			t.Errorf("NextDelay(%d) = %v, %v, want %v", attempt, delay, ok, want)
+		}
+	}
+	if _, ok := policy.NextDelay(4, 0); ok {
+		t.Error("NextDelay beyond MaxAttempts should give up")
+	}
+	policy = &ReconnectBackoff{BaseDelay: time.Second, MaxElapsed: time.Minute}
+	if _, ok := policy.NextDelay(1, time.Minute); ok {
+		t.Error("NextDelay beyond MaxElapsed should give up")
+	}
+	policy = &ReconnectBackoff{BaseDelay: time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if delay, _ := policy.NextDelay(1, 0); delay < 500*time.Millisecond || delay > 1500*time.Millisecond {
+			t.Fatalf("NextDelay with jitter = %v, want within 50%% of 1s", delay)
+		}
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, delay := range want {
+		if got := policy.Backoff(i + 1); got != delay {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, delay)
+		}
+	}
+	if !policy.CanRetry(2) || policy.CanRetry(3) {
+		t.Error("CanRetry should allow attempts below MaxAttempts")
+	}
+	var none *RetryPolicy
+	if none.CanRetry(1) {
+		t.Error("nil policy should not retry")
+	}
+}
+
+func TestNewIsolated(t *testing.T) {
+	custom := New(WithHost("api.example.com"), WithRequestTimeout(time.Second), WithUserAgent("test"))
+	if custom.Host != "api.example.com" || custom.WsTradeV2Endpoint.String() != "wss://api.example.com/ws/v2" {
+		t.Errorf("custom endpoints = %s %s", custom.Host, custom.WsTradeV2Endpoint)
+	}
+	if custom.RequestTimeout != time.Second || custom.UserAgent != "test" {
+		t.Errorf("custom = %+v", custom)
+	}
+	// 单个Client的配置项不影响全局配置及其它Client
+	defaults := New()
+	if HuobiApiHost == "api.example.com" || defaults.Host != HuobiApiHost || defaults.UserAgent != DefaultUserAgent {
+		t.Errorf("defaults = %+v", defaults)
+	}
+	if defaults.RestEndpoint == custom.RestEndpoint {
+		t.Error("clients share endpoint")
+	}
+}
@@ -15,3 +15,24 @@ func Println(a ...interface{}) {
 		log.Println(a...)
 	}
 }
+
+// Logger 单个Client的调试日志，nil Logger 跟随全局 Debug 设置
+type Logger struct {
+	output bool
+}
+
+// NewLogger 创建独立于全局设置的调试日志
+func NewLogger(output bool) *Logger {
+	return &Logger{output: output}
+}
+
+// Println 输出调试日志
+func (logger *Logger) Println(a ...interface{}) {
+	if logger == nil {
+		Println(a...)
+		return
+	}
+	if logger.output {
+		log.Println(a...)
+	}
+}
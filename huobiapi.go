@@ -1,14 +1,26 @@
 package huobiapi
 
 import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/apierror"
 	"github.com/feeeei/huobiapi-go/config"
 	"github.com/feeeei/huobiapi-go/debug"
+	"github.com/feeeei/huobiapi-go/keystore"
 	"github.com/feeeei/huobiapi-go/restclient"
+	"github.com/feeeei/huobiapi-go/sign"
+	"github.com/feeeei/huobiapi-go/sign/signd"
 	"github.com/feeeei/huobiapi-go/wsclient"
+	"github.com/gorilla/websocket"
 )
 
 type Params = map[string]interface{}
 
+// Option Client配置项，未指定的项使用全局配置
+type Option = config.Option
+
 // MarketClient REST格式市场client
 type MarketClient = restclient.MarketClient
 
@@ -24,6 +36,57 @@ type TradeWSClient = wsclient.TradeWSClient
 //TradeWSV2Client WebSocket格式交易clientV2
 type TradeWSV2Client = wsclient.TradeWSV2Client
 
+// ConnState WebSocket 连接状态
+type ConnState = wsclient.ConnState
+
+// WebSocket 连接状态
+const (
+	StateConnecting   = wsclient.StateConnecting
+	StateConnected    = wsclient.StateConnected
+	StateDisconnected = wsclient.StateDisconnected
+	StateReconnecting = wsclient.StateReconnecting
+	StateClosed       = wsclient.StateClosed
+)
+
+// Hooks WebSocket 连接状态事件回调
+type Hooks = config.Hooks
+
+// APIError 火币接口返回的错误，包含错误码、HTTP状态码及原始响应
+type APIError = apierror.APIError
+
+// 错误分类，可配合 errors.Is 判断
+var (
+	ErrInsufficientBalance = apierror.ErrInsufficientBalance
+	ErrRateLimited         = apierror.ErrRateLimited
+	ErrOrderNotFound       = apierror.ErrOrderNotFound
+	ErrSignatureInvalid    = apierror.ErrSignatureInvalid
+	ErrConnectionClosed    = wsclient.ErrConnectionClosed
+	ErrRequestTimeout      = wsclient.ErrRequestTimeout
+	ErrClientClosed        = wsclient.ErrClientClosed
+	ErrReconnectGaveUp     = wsclient.ErrReconnectGaveUp
+	ErrProxyNotApplied     = config.ErrProxyNotApplied
+)
+
+// IsInsufficientBalance 是否为余额不足错误
+func IsInsufficientBalance(err error) bool {
+	return apierror.IsInsufficientBalance(err)
+}
+
+// IsRateLimited 是否为请求频率超限错误
+func IsRateLimited(err error) bool {
+	return apierror.IsRateLimited(err)
+}
+
+// IsOrderNotFound 是否为订单不存在错误
+func IsOrderNotFound(err error) bool {
+	return apierror.IsOrderNotFound(err)
+}
+
+// IsSignatureInvalid 是否为签名校验失败错误
+func IsSignatureInvalid(err error) bool {
+	return apierror.IsSignatureInvalid(err)
+}
+
 func init() {
 	config.SetAPIHost("api.huobi.pro")
 }
@@ -43,27 +106,197 @@ func DebugMode(output bool) {
 	debug.Debug(output)
 }
 
+// WithHost Client使用指定Host，不影响全局配置
+func WithHost(host string) Option {
+	return config.WithHost(host)
+}
+
+// WithAWSHost Client使用aws域名
+func WithAWSHost() Option {
+	return config.WithHost("api-aws.huobi.pro")
+}
+
+// WithHTTPClient Client使用指定的 http.Client 发送REST请求
+func WithHTTPClient(client *http.Client) Option {
+	return config.WithHTTPClient(client)
+}
+
+// WithUserAgent 设置Client的 User-Agent
+func WithUserAgent(userAgent string) Option {
+	return config.WithUserAgent(userAgent)
+}
+
+// WithDialer Client使用指定的 websocket.Dialer 建立连接
+func WithDialer(dialer *websocket.Dialer) Option {
+	return config.WithDialer(dialer)
+}
+
+// WithProxy Client的REST请求与WebSocket连接均通过指定代理
+func WithProxy(proxyURL *url.URL) Option {
+	return config.WithProxy(proxyURL)
+}
+
+// RateLimiter REST请求限频器接口，可自行实现
+type RateLimiter = config.RateLimiter
+
+// UIDRateLimiter 按接口分组限频的REST客户端限频器
+type UIDRateLimiter = restclient.RateLimiter
+
+// LimitMode 达到限频时的处理方式
+type LimitMode = restclient.LimitMode
+
+const (
+	LimitBlock    = restclient.LimitBlock    // 阻塞等待至下个窗口
+	LimitFailFast = restclient.LimitFailFast // 立即返回限频错误
+)
+
+// EndpointCategory 限频分组
+type EndpointCategory = restclient.EndpointCategory
+
+const (
+	CategoryMarket      = restclient.CategoryMarket
+	CategoryOrderPlace  = restclient.CategoryOrderPlace
+	CategoryOrderCancel = restclient.CategoryOrderCancel
+	CategoryOrderQuery  = restclient.CategoryOrderQuery
+	CategoryAlgoOrder   = restclient.CategoryAlgoOrder
+	CategoryAccount     = restclient.CategoryAccount
+	CategoryWallet      = restclient.CategoryWallet
+	CategoryMargin      = restclient.CategoryMargin
+	CategorySubUser     = restclient.CategorySubUser
+	CategoryDefault     = restclient.CategoryDefault
+)
+
+// RateLimit 一个限频窗口内允许的请求次数
+type RateLimit = restclient.RateLimit
+
+// NewRateLimiter 创建按接口分组限频的限频器，mode 为 LimitBlock 或 LimitFailFast
+func NewRateLimiter(mode LimitMode, limits ...map[EndpointCategory]RateLimit) *UIDRateLimiter {
+	return restclient.NewRateLimiter(mode, limits...)
+}
+
+// WithRateLimiter Client的REST请求使用指定的限频器，同一UID的多个Client应共享同一个限频器
+func WithRateLimiter(limiter RateLimiter) Option {
+	return config.WithRateLimiter(limiter)
+}
+
+// RetryPolicy REST请求重试策略
+type RetryPolicy = config.RetryPolicy
+
+// DefaultRetryPolicy 默认重试策略，最多请求3次
+var DefaultRetryPolicy = config.DefaultRetryPolicy
+
+// WithRetry Client的REST请求在网络错误、5xx及限频时按策略重试，
+// 非幂等请求仅在确认服务端未处理时重试，下单会按 client-order-id 防止重复
+func WithRetry(policy RetryPolicy) Option {
+	return config.WithRetry(policy)
+}
+
+// ClockSync 与服务器对时的签名时间来源
+type ClockSync = restclient.ClockSync
+
+// NewClockSync 创建对时组件，通过 Start 启动后台定期对时
+func NewClockSync(client *MarketClient, interval time.Duration) *ClockSync {
+	return restclient.NewClockSync(client, interval)
+}
+
+// WithClock Client签名时使用指定的时间来源，如 ClockSync
+func WithClock(clock sign.Clock) Option {
+	return config.WithClock(clock)
+}
+
+// Signer 签名算法
+type Signer = sign.Signer
+
+// LoadEd25519SignerFile 从 PEM 格式的 Ed25519 私钥文件创建签名
+func LoadEd25519SignerFile(path string) (Signer, error) {
+	signer, err := sign.LoadEd25519SignerFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// DialSigner 连接签名服务，返回的 Signer 可通过 WithSigner 传入各交易Client
+func DialSigner(socketPath string, timeout time.Duration) (*signd.Client, error) {
+	return signd.Dial(socketPath, timeout)
+}
+
+// Keystore 加密密钥库
+type Keystore = keystore.Keystore
+
+// Credential 密钥库中的一组 API 密钥
+type Credential = keystore.Credential
+
+// CreateKeystore 创建加密密钥库，添加密钥后调用 Save 写入文件
+func CreateKeystore(path, passphrase string) (*Keystore, error) {
+	return keystore.Create(path, passphrase)
+}
+
+// OpenKeystore 使用口令打开加密密钥库
+func OpenKeystore(path, passphrase string) (*Keystore, error) {
+	return keystore.Open(path, passphrase)
+}
+
+// WithSigner Client使用指定的签名算法进行REST签名及WebSocket鉴权
+func WithSigner(signer Signer) Option {
+	return config.WithSigner(signer)
+}
+
+// WithHeartbeat 设置Client的WebSocket心跳间隔
+func WithHeartbeat(duration time.Duration) Option {
+	return config.WithHeartbeat(duration)
+}
+
+// WithRequestTimeout 设置WebSocket订阅、请求及鉴权等待响应的超时时间，为0时不超时
+func WithRequestTimeout(timeout time.Duration) Option {
+	return config.WithRequestTimeout(timeout)
+}
+
+// ReconnectPolicy WebSocket 断线重连策略
+type ReconnectPolicy = config.ReconnectPolicy
+
+// ReconnectBackoff 指数退避重连策略
+type ReconnectBackoff = config.ReconnectBackoff
+
+// DefaultReconnectBackoff 默认重连策略，不会放弃重连
+var DefaultReconnectBackoff = config.DefaultReconnectBackoff
+
+// WithReconnect 设置WebSocket断线重连策略
+func WithReconnect(policy ReconnectPolicy) Option {
+	return config.WithReconnect(policy)
+}
+
+// WithHooks 设置WebSocket连接状态事件回调
+func WithHooks(hooks Hooks) Option {
+	return config.WithHooks(hooks)
+}
+
+// WithDebug 单独设置Client是否打印调试日志
+func WithDebug(output bool) Option {
+	return config.WithDebug(output)
+}
+
 // NewMarketClient 创建REST行情Client
-func NewMarketClient() (*MarketClient, error) {
-	return restclient.NewMarketClient()
+func NewMarketClient(options ...Option) (*MarketClient, error) {
+	return restclient.NewMarketClient(options...)
 }
 
 // NewTradeClient 创建REST交易Client
-func NewTradeClient(accessKeyID, accessKeySecret string) (*TradeClient, error) {
-	return restclient.NewTradeClient(accessKeyID, accessKeySecret)
+func NewTradeClient(accessKeyID, accessKeySecret string, options ...Option) (*TradeClient, error) {
+	return restclient.NewTradeClient(accessKeyID, accessKeySecret, options...)
 }
 
 // NewMarketWSClient 创建WebSocket行情Client
-func NewMarketWSClient() (*MarketWSClient, error) {
-	return wsclient.NewMarketWSClient()
+func NewMarketWSClient(options ...Option) (*MarketWSClient, error) {
+	return wsclient.NewMarketWSClient(options...)
 }
 
 // NewTradeWSClient 创建WebSocket交易Client
-func NewTradeWSClient(accessKeyID, accessKeySecret string) (*TradeWSClient, error) {
-	return wsclient.NewTradeWSClient(accessKeyID, accessKeySecret)
+func NewTradeWSClient(accessKeyID, accessKeySecret string, options ...Option) (*TradeWSClient, error) {
+	return wsclient.NewTradeWSClient(accessKeyID, accessKeySecret, options...)
 }
 
 // NewTradeWSV2Client 创建WebSocket交易Client
-func NewTradeWSV2Client(accessKeyID, accessKeySecret string) (*TradeWSV2Client, error) {
-	return wsclient.NewTradeWSV2Client(accessKeyID, accessKeySecret)
+func NewTradeWSV2Client(accessKeyID, accessKeySecret string, options ...Option) (*TradeWSV2Client, error) {
+	return wsclient.NewTradeWSV2Client(accessKeyID, accessKeySecret, options...)
 }
@@ -0,0 +1,13 @@
+package huobiapi
+
+import "testing"
+
+func TestLoadEd25519SignerFileError(t *testing.T) {
+	signer, err := LoadEd25519SignerFile("testdata/missing.pem")
+	if err == nil {
+		t.Fatal("want error")
+	}
+	if signer != nil {
+		t.Errorf("signer = %#v, want nil interface", signer)
+	}
+}
@@ -0,0 +1,333 @@
+// Package keystore 加密保存多组火币 API 密钥，
+// 使用 PBKDF2-HMAC-SHA256 从口令派生密钥，AES-256-GCM 加密及校验文件内容。
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/feeeei/huobiapi-go/config"
+	"github.com/feeeei/huobiapi-go/restclient"
+	"github.com/feeeei/huobiapi-go/sign"
+	"github.com/feeeei/huobiapi-go/wsclient"
+)
+
+const (
+	fileVersion       = 1
+	kdfName           = "pbkdf2-sha256"
+	cipherName        = "aes-256-gcm"
+	DefaultIterations = 600000
+	MinIterations     = 10000
+	saltSize          = 16
+	keySize           = 32
+)
+
+var (
+	// ErrInvalidPassphrase 口令错误或文件已被篡改
+	ErrInvalidPassphrase = errors.New("keystore: invalid passphrase or corrupted file")
+	// ErrKeyNotFound 指定名称的密钥不存在
+	ErrKeyNotFound = errors.New("keystore: key not found")
+)
+
+// Credential 一组 API 密钥，PrivateKey 不为空时使用 Ed25519 签名，否则使用 AccessKeySecret 进行 HmacSHA256 签名
+type Credential struct {
+	AccessKeyID     string `json:"accessKeyId"`
+	AccessKeySecret string `json:"accessKeySecret,omitempty"`
+	PrivateKey      string `json:"privateKey,omitempty"` // PEM 格式 Ed25519 私钥
+}
+
+// Options 返回使用该密钥签名所需的Client配置项
+func (cred *Credential) Options() ([]config.Option, error) {
+	if cred.PrivateKey == "" {
+		return nil, nil
+	}
+	signer, err := sign.LoadEd25519Signer([]byte(cred.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	return []config.Option{config.WithSigner(signer)}, nil
+}
+
+// fileFormat 密钥文件格式，ciphertext 为 name->Credential 的 JSON 加密结果
+type fileFormat struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Cipher     string `json:"cipher"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Keystore 加密密钥库，修改后需调用 Save 写回文件
+type Keystore struct {
+	path       string
+	passphrase []byte
+	iterations int
+	keys       map[string]Credential
+	m          sync.RWMutex
+}
+
+// Create 创建空的密钥库，文件已存在时返回错误
+func Create(path, passphrase string) (*Keystore, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("keystore: %s already exists", path)
+	}
+	return &Keystore{
+		path:       path,
+		passphrase: []byte(passphrase),
+		iterations: DefaultIterations,
+		keys:       make(map[string]Credential),
+	}, nil
+}
+
+// Open 使用口令打开已有的密钥库
+func Open(path, passphrase string) (*Keystore, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file fileFormat
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("keystore: %v", err)
+	}
+	if file.Version != fileVersion || file.KDF != kdfName || file.Cipher != cipherName {
+		return nil, fmt.Errorf("keystore: unsupported file format version=%d kdf=%s cipher=%s", file.Version, file.KDF, file.Cipher)
+	}
+	if file.Iterations <= 0 {
+		return nil, fmt.Errorf("keystore: invalid iterations %d", file.Iterations)
+	}
+
+	aead, err := newAEAD([]byte(passphrase), file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != aead.NonceSize() {
+		return nil, ErrInvalidPassphrase
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, additionalData(&file))
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+	defer wipe(plaintext)
+
+	keys := make(map[string]Credential)
+	if err := json.Unmarshal(plaintext, &keys); err != nil {
+		return nil, fmt.Errorf("keystore: %v", err)
+	}
+	return &Keystore{
+		path:       path,
+		passphrase: []byte(passphrase),
+		iterations: file.Iterations,
+		keys:       keys,
+	}, nil
+}
+
+// SetIterations 设置下次 Save 时的 PBKDF2 迭代次数，不得小于 MinIterations
+func (ks *Keystore) SetIterations(iterations int) error {
+	if err := checkIterations(iterations); err != nil {
+		return err
+	}
+	ks.m.Lock()
+	defer ks.m.Unlock()
+	ks.iterations = iterations
+	return nil
+}
+
+// ChangePassphrase 修改口令，下次 Save 时生效
+func (ks *Keystore) ChangePassphrase(passphrase string) {
+	ks.m.Lock()
+	defer ks.m.Unlock()
+	wipe(ks.passphrase)
+	ks.passphrase = []byte(passphrase)
+}
+
+// Put 保存或覆盖指定名称的密钥
+func (ks *Keystore) Put(name string, cred Credential) error {
+	if name == "" || cred.AccessKeyID == "" {
+		return fmt.Errorf("keystore: name and AccessKeyID are required")
+	}
+	if cred.AccessKeySecret == "" && cred.PrivateKey == "" {
+		return fmt.Errorf("keystore: AccessKeySecret or PrivateKey is required")
+	}
+	if _, err := cred.Options(); err != nil {
+		return err
+	}
+	ks.m.Lock()
+	defer ks.m.Unlock()
+	ks.keys[name] = cred
+	return nil
+}
+
+// Get 获取指定名称的密钥
+func (ks *Keystore) Get(name string) (Credential, error) {
+	ks.m.RLock()
+	defer ks.m.RUnlock()
+	cred, isExist := ks.keys[name]
+	if !isExist {
+		return Credential{}, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+	return cred, nil
+}
+
+// Delete 删除指定名称的密钥
+func (ks *Keystore) Delete(name string) {
+	ks.m.Lock()
+	defer ks.m.Unlock()
+	delete(ks.keys, name)
+}
+
+// Names 按字母序返回所有密钥名称
+func (ks *Keystore) Names() []string {
+	ks.m.RLock()
+	defer ks.m.RUnlock()
+	names := make([]string, 0, len(ks.keys))
+	for name := range ks.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save 使用新的盐值及随机数重新加密并写回文件，文件权限为 0600
+func (ks *Keystore) Save() error {
+	ks.m.RLock()
+	defer ks.m.RUnlock()
+	if err := checkIterations(ks.iterations); err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(ks.keys)
+	if err != nil {
+		return err
+	}
+	defer wipe(plaintext)
+
+	file := fileFormat{
+		Version:    fileVersion,
+		KDF:        kdfName,
+		Iterations: ks.iterations,
+		Salt:       make([]byte, saltSize),
+		Cipher:     cipherName,
+	}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	aead, err := newAEAD(ks.passphrase, file.Salt, file.Iterations)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Ciphertext = aead.Seal(nil, file.Nonce, plaintext, additionalData(&file))
+
+	b, err := json.MarshalIndent(&file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(ks.path, b)
+}
+
+// NewTradeClient 使用指定名称的密钥创建REST交易Client
+func (ks *Keystore) NewTradeClient(name string, options ...config.Option) (*restclient.TradeClient, error) {
+	cred, opts, err := ks.credential(name, options)
+	if err != nil {
+		return nil, err
+	}
+	return restclient.NewTradeClient(cred.AccessKeyID, cred.AccessKeySecret, opts...)
+}
+
+// NewTradeWSClient 使用指定名称的密钥创建WebSocket交易Client
+func (ks *Keystore) NewTradeWSClient(name string, options ...config.Option) (*wsclient.TradeWSClient, error) {
+	cred, opts, err := ks.credential(name, options)
+	if err != nil {
+		return nil, err
+	}
+	return wsclient.NewTradeWSClient(cred.AccessKeyID, cred.AccessKeySecret, opts...)
+}
+
+// NewTradeWSV2Client 使用指定名称的密钥创建WebSocket V2交易Client
+func (ks *Keystore) NewTradeWSV2Client(name string, options ...config.Option) (*wsclient.TradeWSV2Client, error) {
+	cred, opts, err := ks.credential(name, options)
+	if err != nil {
+		return nil, err
+	}
+	return wsclient.NewTradeWSV2Client(cred.AccessKeyID, cred.AccessKeySecret, opts...)
+}
+
+// credential 获取密钥及签名配置项，调用方传入的配置项优先
+func (ks *Keystore) credential(name string, options []config.Option) (Credential, []config.Option, error) {
+	cred, err := ks.Get(name)
+	if err != nil {
+		return cred, nil, err
+	}
+	opts, err := cred.Options()
+	if err != nil {
+		return cred, nil, err
+	}
+	return cred, append(opts, options...), nil
+}
+
+func checkIterations(iterations int) error {
+	if iterations < MinIterations {
+		return fmt.Errorf("keystore: iterations %d is less than %d", iterations, MinIterations)
+	}
+	return nil
+}
+
+func newAEAD(passphrase, salt []byte, iterations int) (cipher.AEAD, error) {
+	key := pbkdf2SHA256(passphrase, salt, iterations, keySize)
+	defer wipe(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// additionalData 将文件头参数纳入校验，防止篡改迭代次数等参数
+func additionalData(file *fileFormat) []byte {
+	return []byte(fmt.Sprintf("%d|%s|%d|%s", file.Version, file.KDF, file.Iterations, file.Cipher))
+}
+
+// writeFile 先写入临时文件再重命名，避免写入中断导致密钥库损坏
+func writeFile(path string, b []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,204 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func tempPath(t *testing.T) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "keys.json"), func() { os.RemoveAll(dir) }
+}
+
+const testSecret = "b0a3a2c1-5e6f7a8b-plain-secret"
+
+// createKeystore 创建并保存包含一个 HMAC 密钥的密钥库，迭代次数取最小值以加快测试
+func createKeystore(t *testing.T, path string) *Keystore {
+	t.Helper()
+	ks, err := Create(path, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.SetIterations(MinIterations); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Put("main", Credential{AccessKeyID: "ak", AccessKeySecret: testSecret}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return ks
+}
+
+func TestRoundTrip(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	createKeystore(t, path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+	if b, _ := ioutil.ReadFile(path); bytes.Contains(b, []byte(testSecret)) {
+		t.Error("file contains plaintext secret")
+	}
+
+	ks, err := Open(path, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cred, err := ks.Get("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred != (Credential{AccessKeyID: "ak", AccessKeySecret: testSecret}) {
+		t.Errorf("Get = %+v", cred)
+	}
+	if _, err := ks.Get("other"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Get(other) = %v, want ErrKeyNotFound", err)
+	}
+
+	ks.Put("sub", Credential{AccessKeyID: "ak2", AccessKeySecret: "sk2"})
+	ks.ChangePassphrase("new passphrase")
+	if err := ks.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, "passphrase"); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("Open with old passphrase = %v", err)
+	}
+	ks, err = Open(path, "new passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := ks.Names(); !reflect.DeepEqual(names, []string{"main", "sub"}) {
+		t.Errorf("Names = %v", names)
+	}
+	ks.Delete("sub")
+	if names := ks.Names(); !reflect.DeepEqual(names, []string{"main"}) {
+		t.Errorf("Names after Delete = %v", names)
+	}
+}
+
+func TestCreateExisting(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	createKeystore(t, path)
+	if _, err := Create(path, "passphrase"); err == nil {
+		t.Error("Create on existing file should fail")
+	}
+}
+
+func TestWrongPassphrase(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	createKeystore(t, path)
+	if _, err := Open(path, "wrong"); !errors.Is(err, ErrInvalidPassphrase) {
+		t.Errorf("Open = %v, want ErrInvalidPassphrase", err)
+	}
+}
+
+func TestTamper(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	createKeystore(t, path)
+	original, _ := ioutil.ReadFile(path)
+
+	tests := map[string]func(file *fileFormat){
+		"ciphertext": func(file *fileFormat) { file.Ciphertext[0] ^= 1 },
+		"nonce":      func(file *fileFormat) { file.Nonce[0] ^= 1 },
+		"salt":       func(file *fileFormat) { file.Salt[0] ^= 1 },
+		"iterations": func(file *fileFormat) { file.Iterations++ },
+	}
+	for name, tamper := range tests {
+		var file fileFormat
+		json.Unmarshal(original, &file)
+		tamper(&file)
+		b, _ := json.Marshal(&file)
+		ioutil.WriteFile(path, b, 0600)
+		if _, err := Open(path, "passphrase"); !errors.Is(err, ErrInvalidPassphrase) {
+			t.Errorf("tampered %s: Open = %v, want ErrInvalidPassphrase", name, err)
+		}
+	}
+
+	var file fileFormat
+	json.Unmarshal(original, &file)
+	file.Iterations = 0
+	b, _ := json.Marshal(&file)
+	ioutil.WriteFile(path, b, 0600)
+	if _, err := Open(path, "passphrase"); err == nil {
+		t.Error("Open with zero iterations should fail")
+	}
+}
+
+func TestSetIterations(t *testing.T) {
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	ks, err := Create(path, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iterations := range []int{-1, 0, MinIterations - 1} {
+		if err := ks.SetIterations(iterations); err == nil {
+			t.Errorf("SetIterations(%d) should fail", iterations)
+		}
+	}
+	// 跳过 SetIterations 校验时 Save 也不会写入文件
+	ks.iterations = 1
+	if err := ks.Save(); err == nil {
+		t.Error("Save with 1 iteration should fail")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file written with invalid iterations: %v", err)
+	}
+}
+
+func TestEd25519Credential(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	path, cleanup := tempPath(t)
+	defer cleanup()
+	ks, _ := Create(path, "passphrase")
+	if err := ks.Put("ed", Credential{AccessKeyID: "ak", PrivateKey: pemKey}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Put("bad", Credential{AccessKeyID: "ak", PrivateKey: "not a key"}); err == nil {
+		t.Error("Put with invalid private key should fail")
+	}
+	if err := ks.Put("empty", Credential{AccessKeyID: "ak"}); err == nil {
+		t.Error("Put without secret should fail")
+	}
+	cred, _ := ks.Get("ed")
+	options, err := cred.Options()
+	if err != nil || len(options) != 1 {
+		t.Errorf("Options = %d, %v", len(options), err)
+	}
+	if _, err := ks.NewTradeClient("ed"); err != nil {
+		t.Error(err)
+	}
+	if _, err := ks.NewTradeClient("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("NewTradeClient(missing) = %v", err)
+	}
+}
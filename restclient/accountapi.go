@@ -0,0 +1,204 @@
+package restclient
+
+import (
+	"fmt"
+)
+
+// Account 账户信息
+type Account struct {
+	ID      int64  `json:"id"`
+	Type    string `json:"type"`    // spot、margin、otc、point、super-margin、investment、borrow
+	Subtype string `json:"subtype"` // 逐仓杠杆账户为交易对
+	State   string `json:"state"`   // working、lock
+}
+
+// BalanceItem 单币种余额
+type BalanceItem struct {
+	Currency string `json:"currency"`
+	Type     string `json:"type"` // trade、frozen、loan、interest 等
+	Balance  string `json:"balance"`
+}
+
+// Balance 账户余额
+type Balance struct {
+	ID    int64         `json:"id"`
+	Type  string        `json:"type"`
+	State string        `json:"state"`
+	List  []BalanceItem `json:"list"`
+}
+
+// AssetValuationQuery 资产估值查询条件，AccountType 必填
+type AssetValuationQuery struct {
+	AccountType       string // spot、margin、otc、super-margin
+	ValuationCurrency string // 估值币种，默认 BTC
+	SubUID            int64
+}
+
+func (query *AssetValuationQuery) params() map[string]interface{} {
+	params := map[string]interface{}{"accountType": query.AccountType}
+	setString(params, "valuationCurrency", query.ValuationCurrency)
+	setInt(params, "subUid", query.SubUID)
+	return params
+}
+
+// AssetValuation 资产估值
+type AssetValuation struct {
+	Balance   string `json:"balance"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// AccountHistoryQuery 账户流水查询条件，AccountID 必填
+type AccountHistoryQuery struct {
+	AccountID     int64
+	Currency      string
+	TransactTypes []string
+	StartTime     int64  // 毫秒时间戳
+	EndTime       int64  // 毫秒时间戳
+	Sort          string // asc、desc
+	Size          int
+	FromID        int64
+}
+
+func (query *AccountHistoryQuery) params() map[string]interface{} {
+	params := map[string]interface{}{"account-id": query.AccountID}
+	setString(params, "currency", query.Currency)
+	setStrings(params, "transact-types", query.TransactTypes)
+	setInt(params, "start-time", query.StartTime)
+	setInt(params, "end-time", query.EndTime)
+	setString(params, "sort", query.Sort)
+	setInt(params, "size", int64(query.Size))
+	setInt(params, "from-id", query.FromID)
+	return params
+}
+
+// AccountHistory 账户流水
+type AccountHistory struct {
+	AccountID    int64  `json:"account-id"`
+	Currency     string `json:"currency"`
+	TransactAmt  string `json:"transact-amt"`
+	TransactType string `json:"transact-type"`
+	RecordID     int64  `json:"record-id"`
+	AvailBalance string `json:"avail-balance"`
+	AcctBalance  string `json:"acct-balance"`
+	TransactTime int64  `json:"transact-time"`
+}
+
+// LedgerQuery 财务流水查询条件，AccountID 必填
+type LedgerQuery struct {
+	AccountID     int64
+	Currency      string
+	TransactTypes []string
+	StartTime     int64  // 毫秒时间戳
+	EndTime       int64  // 毫秒时间戳
+	Sort          string // asc、desc
+	Limit         int
+	FromID        int64
+}
+
+func (query *LedgerQuery) params() map[string]interface{} {
+	params := map[string]interface{}{"accountId": query.AccountID}
+	setString(params, "currency", query.Currency)
+	setStrings(params, "transactTypes", query.TransactTypes)
+	setInt(params, "startTime", query.StartTime)
+	setInt(params, "endTime", query.EndTime)
+	setString(params, "sort", query.Sort)
+	setInt(params, "limit", int64(query.Limit))
+	setInt(params, "fromId", query.FromID)
+	return params
+}
+
+// Ledger 财务流水
+type Ledger struct {
+	AccountID    int64  `json:"accountId"`
+	Currency     string `json:"currency"`
+	TransactAmt  string `json:"transactAmt"`
+	TransactType string `json:"transactType"`
+	TransferType string `json:"transferType"`
+	TransactID   int64  `json:"transactId"`
+	TransactTime int64  `json:"transactTime"`
+	Transferer   int64  `json:"transferer"`
+	Transferee   int64  `json:"transferee"`
+}
+
+// Accounts 查询当前用户的所有账户
+func (client *TradeClient) Accounts() ([]Account, error) {
+	var accounts []Account
+	_, err := client.HandleGet("/v1/account/accounts", &accounts)
+	return accounts, err
+}
+
+// Balance 查询指定账户的余额
+func (client *TradeClient) Balance(accountID int64) (*Balance, error) {
+	var balance Balance
+	if _, err := client.HandleGet(fmt.Sprintf("/v1/account/accounts/%d/balance", accountID), &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// AssetValuation 查询指定类型账户的总资产估值
+func (client *TradeClient) AssetValuation(query *AssetValuationQuery) (*AssetValuation, error) {
+	var valuation AssetValuation
+	if _, err := client.HandleGet("/v2/account/asset-valuation", &valuation, query.params()); err != nil {
+		return nil, err
+	}
+	return &valuation, nil
+}
+
+// AccountHistory 查询一页账户流水，nextID 为下一页的起始ID，为0时表示没有更多数据
+func (client *TradeClient) AccountHistory(query *AccountHistoryQuery) (history []AccountHistory, nextID int64, err error) {
+	resp, err := client.HandleGet("/v1/account/history", &history, query.params())
+	if err != nil {
+		return nil, 0, err
+	}
+	return history, resp.Get("next-id").MustInt64(), nil
+}
+
+// ForEachAccountHistory 自动翻页遍历全部账户流水，fn 返回错误时停止遍历并返回该错误
+func (client *TradeClient) ForEachAccountHistory(query *AccountHistoryQuery, fn func(history *AccountHistory) error) error {
+	q := *query
+	for {
+		history, nextID, err := client.AccountHistory(&q)
+		if err != nil {
+			return err
+		}
+		for i := range history {
+			if err := fn(&history[i]); err != nil {
+				return err
+			}
+		}
+		if nextID == 0 || nextID == q.FromID || len(history) == 0 {
+			return nil
+		}
+		q.FromID = nextID
+	}
+}
+
+// Ledger 查询一页财务流水，nextID 为下一页的起始ID，为0时表示没有更多数据
+func (client *TradeClient) Ledger(query *LedgerQuery) (ledgers []Ledger, nextID int64, err error) {
+	resp, err := client.HandleGet("/v2/account/ledger", &ledgers, query.params())
+	if err != nil {
+		return nil, 0, err
+	}
+	return ledgers, resp.Get("nextId").MustInt64(), nil
+}
+
+// ForEachLedger 自动翻页遍历全部财务流水，fn 返回错误时停止遍历并返回该错误
+func (client *TradeClient) ForEachLedger(query *LedgerQuery, fn func(ledger *Ledger) error) error {
+	q := *query
+	for {
+		ledgers, nextID, err := client.Ledger(&q)
+		if err != nil {
+			return err
+		}
+		for i := range ledgers {
+			if err := fn(&ledgers[i]); err != nil {
+				return err
+			}
+		}
+		if nextID == 0 || nextID == q.FromID || len(ledgers) == 0 {
+			return nil
+		}
+		q.FromID = nextID
+	}
+}
@@ -0,0 +1,83 @@
+package restclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestBalance(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"GET /v1/account/accounts/100009/balance": `{"status":"ok","data":{"id":100009,"type":"spot","state":"working","list":[{"currency":"usdt","type":"trade","balance":"91.850043797676510303"}]}}`,
+		"GET /v2/account/asset-valuation":         `{"code":200,"success":true,"data":{"balance":"34.75","timestamp":1594901254363}}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+
+	balance, err := client.Balance(100009)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balance.List) != 1 || balance.List[0].Balance != "91.850043797676510303" {
+		t.Errorf("balance = %+v", balance)
+	}
+
+	valuation, err := client.AssetValuation(&AssetValuationQuery{AccountType: "spot", ValuationCurrency: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, query, _ := api.request()
+	if query.Get("accountType") != "spot" || query.Get("valuationCurrency") != "USD" || query.Get("subUid") != "" {
+		t.Errorf("query = %v", query)
+	}
+	if valuation.Balance != "34.75" {
+		t.Errorf("valuation = %+v", valuation)
+	}
+}
+
+// historyPages 按 from-id 返回账户流水分页，每页两条，共五条
+func historyPages(w http.ResponseWriter, r *http.Request) {
+	from := 1
+	fmt.Sscan(r.URL.Query().Get("from-id"), &from)
+	next := from + 2
+	if next > 5 {
+		next = 0
+	}
+	records := fmt.Sprintf(`{"record-id":%d,"transact-amt":"1"}`, from)
+	if from < 5 {
+		records += fmt.Sprintf(`,{"record-id":%d,"transact-amt":"1"}`, from+1)
+	}
+	writeJSON(w, http.StatusOK, fmt.Sprintf(`{"status":"ok","data":[%s],"next-id":%d}`, records, next))
+}
+
+func TestForEachAccountHistory(t *testing.T) {
+	ts := newTestServer(historyPages)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	var ids []int64
+	err := client.ForEachAccountHistory(&AccountHistoryQuery{AccountID: 1}, func(history *AccountHistory) error {
+		ids = append(ids, history.RecordID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[1 2 3 4 5]" {
+		t.Errorf("ids = %v, want [1 2 3 4 5]", ids)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = client.ForEachAccountHistory(&AccountHistoryQuery{AccountID: 1}, func(history *AccountHistory) error {
+		count++
+		if count == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || count != 3 {
+		t.Errorf("err = %v, count = %d, want stop after 3", err, count)
+	}
+}
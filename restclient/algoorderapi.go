@@ -0,0 +1,162 @@
+package restclient
+
+// AlgoOrderType 策略委托触发后的订单类型
+type AlgoOrderType string
+
+const (
+	AlgoOrderTypeLimit  AlgoOrderType = "limit"
+	AlgoOrderTypeMarket AlgoOrderType = "market"
+)
+
+// AlgoOrderStatus 策略委托状态
+type AlgoOrderStatus string
+
+const (
+	AlgoOrderStatusCreated   AlgoOrderStatus = "created"
+	AlgoOrderStatusCanceled  AlgoOrderStatus = "canceled"
+	AlgoOrderStatusRejected  AlgoOrderStatus = "rejected"
+	AlgoOrderStatusTriggered AlgoOrderStatus = "triggered"
+)
+
+// AlgoOrderRequest 策略委托下单参数，ClientOrderID 与 StopPrice 必填。
+// 设置 TrailingRate 时为追踪委托，触发后以 StopPrice 为激活价按回调幅度追踪；
+// 否则为止盈止损委托，OrderType 为 limit 时需指定 OrderPrice
+type AlgoOrderRequest struct {
+	AccountID     int64
+	Symbol        string
+	OrderSide     string // buy、sell
+	OrderType     AlgoOrderType
+	OrderPrice    string
+	OrderSize     string // 限价单及市价卖单的数量
+	OrderValue    string // 市价买单的金额
+	TimeInForce   string // gtc、boc、ioc、fok
+	ClientOrderID string
+	StopPrice     string
+	TrailingRate  string // 回调幅度 [0.001, 0.050]
+}
+
+func (req *AlgoOrderRequest) params() map[string]interface{} {
+	params := map[string]interface{}{
+		"accountId":     req.AccountID,
+		"symbol":        req.Symbol,
+		"orderSide":     req.OrderSide,
+		"orderType":     string(req.OrderType),
+		"clientOrderId": req.ClientOrderID,
+		"stopPrice":     req.StopPrice,
+	}
+	setString(params, "orderPrice", req.OrderPrice)
+	setString(params, "orderSize", req.OrderSize)
+	setString(params, "orderValue", req.OrderValue)
+	setString(params, "timeInForce", req.TimeInForce)
+	setString(params, "trailingRate", req.TrailingRate)
+	return params
+}
+
+// AlgoOrder 策略委托详情
+type AlgoOrder struct {
+	AccountID       int64           `json:"accountId"`
+	Source          string          `json:"source"`
+	ClientOrderID   string          `json:"clientOrderId"`
+	OrderID         string          `json:"orderId"` // 触发后生成的订单ID
+	Symbol          string          `json:"symbol"`
+	OrderSide       string          `json:"orderSide"`
+	OrderType       AlgoOrderType   `json:"orderType"`
+	OrderPrice      string          `json:"orderPrice"`
+	OrderSize       string          `json:"orderSize"`
+	OrderValue      string          `json:"orderValue"`
+	TimeInForce     string          `json:"timeInForce"`
+	StopPrice       string          `json:"stopPrice"`
+	TrailingRate    string          `json:"trailingRate"`
+	OrderStatus     AlgoOrderStatus `json:"orderStatus"`
+	OrderOrigTime   int64           `json:"orderOrigTime"`
+	OrderCreateTime int64           `json:"orderCreateTime"`
+	LastActTime     int64           `json:"lastActTime"`
+	ErrCode         int             `json:"errCode"`
+	ErrMessage      string          `json:"errMessage"`
+}
+
+// AlgoOrdersQuery 策略委托查询条件，查询历史委托时 Symbol 与 OrderStatus 必填
+type AlgoOrdersQuery struct {
+	AccountID   int64
+	Symbol      string
+	OrderSide   string // buy、sell
+	OrderType   AlgoOrderType
+	OrderStatus AlgoOrderStatus // 仅历史委托，canceled、rejected、triggered
+	StartTime   int64           // 仅历史委托，毫秒时间戳
+	EndTime     int64           // 仅历史委托，毫秒时间戳
+	Sort        string          // asc、desc
+	Limit       int
+	FromID      int64
+}
+
+func (query *AlgoOrdersQuery) params() map[string]interface{} {
+	params := map[string]interface{}{}
+	setInt(params, "accountId", query.AccountID)
+	setString(params, "symbol", query.Symbol)
+	setString(params, "orderSide", query.OrderSide)
+	setString(params, "orderType", string(query.OrderType))
+	setString(params, "orderStatus", string(query.OrderStatus))
+	setInt(params, "startTime", query.StartTime)
+	setInt(params, "endTime", query.EndTime)
+	setString(params, "sort", query.Sort)
+	setInt(params, "limit", int64(query.Limit))
+	setInt(params, "fromId", query.FromID)
+	return params
+}
+
+// AlgoCancelResult 策略委托撤单结果
+type AlgoCancelResult struct {
+	Accepted []string `json:"accepted"`
+	Rejected []string `json:"rejected"`
+}
+
+// PlaceAlgoOrder 策略委托下单，返回 client-order-id
+func (client *TradeClient) PlaceAlgoOrder(req *AlgoOrderRequest) (string, error) {
+	var data struct {
+		ClientOrderID string `json:"clientOrderId"`
+	}
+	if _, err := client.HandlePost("/v2/algo-orders", &data, req.params()); err != nil {
+		return "", err
+	}
+	return data.ClientOrderID, nil
+}
+
+// CancelAlgoOrders 根据 client-order-id 批量撤销策略委托，单次最多50个
+func (client *TradeClient) CancelAlgoOrders(clientOrderIDs ...string) (*AlgoCancelResult, error) {
+	var result AlgoCancelResult
+	_, err := client.HandlePost("/v2/algo-orders/cancellation", &result,
+		map[string]interface{}{"clientOrderIds": clientOrderIDs})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// OpenAlgoOrders 查询未触发的策略委托，nextID 为下一页的起始ID，为0时表示没有更多数据
+func (client *TradeClient) OpenAlgoOrders(query *AlgoOrdersQuery) (orders []AlgoOrder, nextID int64, err error) {
+	resp, err := client.HandleGet("/v2/algo-orders/opening", &orders, query.params())
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, resp.Get("nextId").MustInt64(), nil
+}
+
+// AlgoOrderHistory 查询历史策略委托，nextID 为下一页的起始ID，为0时表示没有更多数据
+func (client *TradeClient) AlgoOrderHistory(query *AlgoOrdersQuery) (orders []AlgoOrder, nextID int64, err error) {
+	resp, err := client.HandleGet("/v2/algo-orders/history", &orders, query.params())
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, resp.Get("nextId").MustInt64(), nil
+}
+
+// GetAlgoOrder 根据 client-order-id 查询策略委托详情
+func (client *TradeClient) GetAlgoOrder(clientOrderID string) (*AlgoOrder, error) {
+	var order AlgoOrder
+	_, err := client.HandleGet("/v2/algo-orders/specific", &order,
+		map[string]interface{}{"clientOrderId": clientOrderID})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
@@ -0,0 +1,59 @@
+package restclient
+
+import (
+	"testing"
+)
+
+func TestAlgoOrderAPI(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"POST /v2/algo-orders":              `{"code":200,"data":{"clientOrderId":"a1"}}`,
+		"POST /v2/algo-orders/cancellation": `{"code":200,"data":{"accepted":["a1"],"rejected":["a2"]}}`,
+		"GET /v2/algo-orders/opening":       `{"code":200,"data":[{"clientOrderId":"a1","orderStatus":"created","stopPrice":"48000"}],"nextId":77}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+
+	id, err := client.PlaceAlgoOrder(&AlgoOrderRequest{
+		AccountID:     100009,
+		Symbol:        "btcusdt",
+		OrderSide:     "sell",
+		OrderType:     AlgoOrderTypeMarket,
+		OrderSize:     "0.1",
+		ClientOrderID: "a1",
+		StopPrice:     "48000",
+		TrailingRate:  "0.01",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "a1" {
+		t.Errorf("id = %q, want a1", id)
+	}
+	_, _, _, body := api.request()
+	if body["orderType"] != "market" || body["trailingRate"] != "0.01" || body["accountId"] != float64(100009) {
+		t.Errorf("body = %v", body)
+	}
+	if _, isExist := body["orderPrice"]; isExist {
+		t.Error("empty orderPrice should be omitted")
+	}
+
+	result, err := client.CancelAlgoOrders("a1", "a2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Accepted) != 1 || len(result.Rejected) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+
+	orders, nextID, err := client.OpenAlgoOrders(&AlgoOrdersQuery{Symbol: "btcusdt", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, query, _ := api.request(); query.Get("symbol") != "btcusdt" || query.Get("limit") != "10" || query.Get("accountId") != "" {
+		t.Errorf("query = %v", query)
+	}
+	if len(orders) != 1 || orders[0].OrderStatus != AlgoOrderStatusCreated || nextID != 77 {
+		t.Errorf("orders = %+v, nextID = %d", orders, nextID)
+	}
+}
@@ -0,0 +1,241 @@
+package restclient
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/feeeei/huobiapi-go/apierror"
+	"github.com/feeeei/huobiapi-go/utils"
+)
+
+const (
+	maxBatchPlaceSize  = 10 // 批量下单接口单次最多10单
+	maxBatchCancelSize = 50 // 批量撤单接口单次最多50单
+)
+
+// BatchOrderResult 批量下单中单笔订单的结果
+type BatchOrderResult struct {
+	OrderID       int64
+	ClientOrderID string
+	ErrCode       string
+	ErrMsg        string
+	Err           error // 单笔下单失败或所在批次请求失败时非空
+}
+
+// Success 该笔订单是否下单成功
+func (result *BatchOrderResult) Success() bool {
+	return result.Err == nil
+}
+
+// BatchCancelResult 批量撤单中单笔订单的结果
+type BatchCancelResult struct {
+	OrderID       int64
+	ClientOrderID string
+	OrderState    int // 撤单失败时订单当前状态，-1 表示无法获取
+	ErrCode       string
+	ErrMsg        string
+	Err           error // 单笔撤单失败或所在批次请求失败时非空
+}
+
+// Success 该笔订单是否撤单成功
+func (result *BatchCancelResult) Success() bool {
+	return result.Err == nil
+}
+
+// CancelOpenOrdersRequest 批量撤销未成交订单的条件，AccountID 必填
+type CancelOpenOrdersRequest struct {
+	AccountID int64
+	Symbols   []string // 最多10个交易对，为空时撤销所有交易对
+	Types     []OrderType
+	Side      string // buy、sell
+	Size      int    // 撤销数量，默认100，最大100
+}
+
+func (req *CancelOpenOrdersRequest) params() map[string]interface{} {
+	params := map[string]interface{}{"account-id": fmt.Sprint(req.AccountID)}
+	types := make([]string, len(req.Types))
+	for i, t := range req.Types {
+		types[i] = string(t)
+	}
+	setStrings(params, "symbol", req.Symbols)
+	setStrings(params, "types", types)
+	setString(params, "side", req.Side)
+	setInt(params, "size", int64(req.Size))
+	return params
+}
+
+// CancelOpenOrdersResult 批量撤销未成交订单的结果
+type CancelOpenOrdersResult struct {
+	SuccessCount int   `json:"success-count"`
+	FailedCount  int   `json:"failed-count"`
+	NextID       int64 `json:"next-id"` // -1 表示没有更多符合条件的订单
+}
+
+// BatchPlaceOrders 批量下单，超过单次上限时自动分批提交。
+// 返回结果与 reqs 按下标一一对应，单笔失败记录在对应结果的 Err 中；
+// 某一批次请求失败时，该批次所有结果的 Err 均为该错误，并返回遇到的第一个批次错误
+func (client *TradeClient) BatchPlaceOrders(reqs []*PlaceOrderRequest) ([]BatchOrderResult, error) {
+	results := make([]BatchOrderResult, len(reqs))
+	var firstErr error
+	for start := 0; start < len(reqs); start += maxBatchPlaceSize {
+		end := start + maxBatchPlaceSize
+		if end > len(reqs) {
+			end = len(reqs)
+		}
+		if err := client.batchPlaceOrders(reqs[start:end], results[start:end]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return results, firstErr
+}
+
+func (client *TradeClient) batchPlaceOrders(reqs []*PlaceOrderRequest, results []BatchOrderResult) error {
+	body := make([]map[string]interface{}, len(reqs))
+	for i, req := range reqs {
+		body[i] = req.params()
+		results[i].ClientOrderID = req.ClientOrderID
+	}
+	resp, err := client.post(client.context(), "/v1/order/batch-orders", body)
+	var data []struct {
+		OrderID       int64  `json:"order-id"`
+		ClientOrderID string `json:"client-order-id"`
+		ErrCode       string `json:"err-code"`
+		ErrMsg        string `json:"err-msg"`
+	}
+	if err == nil {
+		_, err = utils.Parse2Obj(resp, &data)
+	}
+	if err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return err
+	}
+
+	// 服务端按提交顺序返回，数量不一致时按 client-order-id 对应
+	byIndex := len(data) == len(reqs)
+	for i, item := range data {
+		index := i
+		if !byIndex {
+			if index = indexOfClientOrderID(reqs, item.ClientOrderID); index < 0 {
+				continue
+			}
+		}
+		result := &results[index]
+		result.OrderID = item.OrderID
+		result.ErrCode = item.ErrCode
+		result.ErrMsg = item.ErrMsg
+		if item.ErrCode != "" || item.ErrMsg != "" {
+			result.Err = &apierror.APIError{Source: apierror.SourceREST, ErrCode: item.ErrCode, Message: item.ErrMsg, Endpoint: "/v1/order/batch-orders"}
+		}
+	}
+	if !byIndex {
+		for i := range results {
+			if results[i].OrderID == 0 && results[i].Err == nil {
+				results[i].Err = fmt.Errorf("Batch order result not found")
+			}
+		}
+	}
+	return nil
+}
+
+func indexOfClientOrderID(reqs []*PlaceOrderRequest, clientOrderID string) int {
+	if clientOrderID == "" {
+		return -1
+	}
+	for i, req := range reqs {
+		if req.ClientOrderID == clientOrderID {
+			return i
+		}
+	}
+	return -1
+}
+
+// BatchCancel 根据订单ID批量撤单，超过单次上限时自动分批提交。
+// 返回结果与 orderIDs 按下标一一对应，错误处理方式同 BatchPlaceOrders
+func (client *TradeClient) BatchCancel(orderIDs []int64) ([]BatchCancelResult, error) {
+	results := make([]BatchCancelResult, len(orderIDs))
+	for i, id := range orderIDs {
+		results[i].OrderID = id
+	}
+	return results, client.batchCancel("order-ids", len(orderIDs), func(i int) string {
+		return strconv.FormatInt(orderIDs[i], 10)
+	}, results)
+}
+
+// BatchCancelByClientOrderIDs 根据 client-order-id 批量撤单，返回结果与 clientOrderIDs 按下标一一对应
+func (client *TradeClient) BatchCancelByClientOrderIDs(clientOrderIDs []string) ([]BatchCancelResult, error) {
+	results := make([]BatchCancelResult, len(clientOrderIDs))
+	for i, id := range clientOrderIDs {
+		results[i].ClientOrderID = id
+	}
+	return results, client.batchCancel("client-order-ids", len(clientOrderIDs), func(i int) string {
+		return clientOrderIDs[i]
+	}, results)
+}
+
+// batchCancel 按上限分批撤单，key 为请求中的ID字段名，id 返回第i个订单的ID
+func (client *TradeClient) batchCancel(key string, count int, id func(i int) string, results []BatchCancelResult) error {
+	var firstErr error
+	for start := 0; start < count; start += maxBatchCancelSize {
+		end := start + maxBatchCancelSize
+		if end > count {
+			end = count
+		}
+		ids := make([]string, 0, end-start)
+		index := make(map[string]int, end-start)
+		for i := start; i < end; i++ {
+			ids = append(ids, id(i))
+			index[id(i)] = i
+		}
+
+		var data struct {
+			Success []string `json:"success"`
+			Failed  []struct {
+				OrderID       string `json:"order-id"`
+				ClientOrderID string `json:"client-order-id"`
+				OrderState    int    `json:"order-state"`
+				ErrCode       string `json:"err-code"`
+				ErrMsg        string `json:"err-msg"`
+			} `json:"failed"`
+		}
+		_, err := client.HandlePost("/v1/order/orders/batchcancel", &data, map[string]interface{}{key: ids})
+		if err != nil {
+			for i := start; i < end; i++ {
+				results[i].Err = err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		for _, failed := range data.Failed {
+			failedID := failed.OrderID
+			if key == "client-order-ids" {
+				failedID = failed.ClientOrderID
+			}
+			i, isExist := index[failedID]
+			if !isExist {
+				continue
+			}
+			results[i].OrderState = failed.OrderState
+			results[i].ErrCode = failed.ErrCode
+			results[i].ErrMsg = failed.ErrMsg
+			results[i].Err = &apierror.APIError{Source: apierror.SourceREST, ErrCode: failed.ErrCode, Message: failed.ErrMsg, Endpoint: "/v1/order/orders/batchcancel"}
+			if id, err := parseID(failed.OrderID); err == nil {
+				results[i].OrderID = id
+			}
+		}
+	}
+	return firstErr
+}
+
+// CancelOpenOrders 按条件批量撤销未成交订单，NextID 不为 -1 时表示仍有订单待撤销
+func (client *TradeClient) CancelOpenOrders(req *CancelOpenOrdersRequest) (*CancelOpenOrdersResult, error) {
+	var result CancelOpenOrdersResult
+	if _, err := client.HandlePost("/v1/order/orders/batchCancelOpenOrders", &result, req.params()); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,134 @@
+package restclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/feeeei/huobiapi-go/apierror"
+)
+
+// batchServer 模拟批量下单及撤单接口，记录每个批次的大小
+type batchServer struct {
+	m       sync.Mutex
+	batches []int
+	failAt  int // 第 failAt 个批次返回5xx，从1开始，为0时不失败
+}
+
+func (server *batchServer) handler(w http.ResponseWriter, r *http.Request) {
+	server.m.Lock()
+	defer server.m.Unlock()
+	switch r.URL.Path {
+	case "/v1/order/batch-orders":
+		var orders []map[string]string
+		json.NewDecoder(r.Body).Decode(&orders)
+		server.batches = append(server.batches, len(orders))
+		if len(server.batches) == server.failAt {
+			writeJSON(w, http.StatusBadGateway, `{}`)
+			return
+		}
+		data := make([]string, len(orders))
+		for i, order := range orders {
+			if order["amount"] == "0" {
+				data[i] = fmt.Sprintf(`{"client-order-id":%q,"err-code":"order-value-min-error","err-msg":"too small"}`, order["client-order-id"])
+			} else {
+				data[i] = fmt.Sprintf(`{"client-order-id":%q,"order-id":%d}`, order["client-order-id"], 1000+len(server.batches)*100+i)
+			}
+		}
+		writeJSON(w, http.StatusOK, `{"status":"ok","data":[`+strings.Join(data, ",")+`]}`)
+	case "/v1/order/orders/batchcancel":
+		var body struct {
+			OrderIDs []string `json:"order-ids"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		server.batches = append(server.batches, len(body.OrderIDs))
+		// 最后一个订单撤单失败，其余成功
+		last := body.OrderIDs[len(body.OrderIDs)-1]
+		success, _ := json.Marshal(body.OrderIDs[:len(body.OrderIDs)-1])
+		writeJSON(w, http.StatusOK, fmt.Sprintf(`{"status":"ok","data":{"success":%s,"failed":[{"order-id":%q,"order-state":7,"err-code":"order-orderstate-error","err-msg":"filled"}]}}`, success, last))
+	}
+}
+
+func placeOrderRequests(count int) []*PlaceOrderRequest {
+	reqs := make([]*PlaceOrderRequest, count)
+	for i := range reqs {
+		reqs[i] = &PlaceOrderRequest{AccountID: 1, Symbol: "btcusdt", Type: OrderTypeBuyLimit, Amount: "1", Price: "1", ClientOrderID: fmt.Sprint("c", i)}
+	}
+	return reqs
+}
+
+func TestBatchPlaceOrders(t *testing.T) {
+	server := &batchServer{}
+	ts := newTestServer(server.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	reqs := placeOrderRequests(12)
+	reqs[11].Amount = "0"
+	results, err := client.BatchPlaceOrders(reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.batches) != 2 || server.batches[0] != 10 || server.batches[1] != 2 {
+		t.Fatalf("batches = %v, want [10 2]", server.batches)
+	}
+	if !results[0].Success() || results[0].OrderID != 1100 || results[10].OrderID != 1200 {
+		t.Errorf("results = %+v", results)
+	}
+	var apiErr *apierror.APIError
+	if results[11].Success() || !errors.As(results[11].Err, &apiErr) || apiErr.ErrCode != "order-value-min-error" {
+		t.Errorf("result 11 = %+v", results[11])
+	}
+	if results[11].ClientOrderID != "c11" {
+		t.Errorf("client order id = %q, want c11", results[11].ClientOrderID)
+	}
+}
+
+func TestBatchPlaceOrdersBatchFailed(t *testing.T) {
+	server := &batchServer{failAt: 2}
+	ts := newTestServer(server.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	results, err := client.BatchPlaceOrders(placeOrderRequests(15))
+	if err == nil {
+		t.Fatal("want error for failed batch")
+	}
+	for i, result := range results {
+		if failed := i >= 10; result.Success() == failed {
+			t.Errorf("result %d success = %v", i, result.Success())
+		}
+		if i >= 10 && result.Err != err {
+			t.Errorf("result %d err = %v, want batch error", i, result.Err)
+		}
+	}
+}
+
+func TestBatchCancel(t *testing.T) {
+	server := &batchServer{}
+	ts := newTestServer(server.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	ids := make([]int64, 60)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	results, err := client.BatchCancel(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(server.batches) != 2 || server.batches[0] != 50 || server.batches[1] != 10 {
+		t.Fatalf("batches = %v, want [50 10]", server.batches)
+	}
+	for i, result := range results {
+		failed := i == 49 || i == 59
+		if result.Success() == failed || result.OrderID != ids[i] {
+			t.Errorf("result %d = %+v", i, result)
+		}
+		if failed && result.OrderState != 7 {
+			t.Errorf("result %d state = %d, want 7", i, result.OrderState)
+		}
+	}
+}
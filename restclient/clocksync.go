@@ -0,0 +1,139 @@
+package restclient
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// clockSyncSamples 每次同步的采样次数，取往返延迟最小的一次计算偏差
+const clockSyncSamples = 3
+
+// ClockSync 定期通过 /v1/common/timestamp 与服务器对时，实现 sign.Clock，
+// 通过 config.WithClock 传入后，REST 及 WebSocket 鉴权签名均使用修正后的时间
+type ClockSync struct {
+	client   *MarketClient
+	interval time.Duration
+	offset   time.Duration
+	rtt      time.Duration
+	synced   time.Time
+	stop     chan struct{}
+	done     chan struct{}
+	m        sync.RWMutex
+}
+
+// NewClockSync 创建对时组件，interval 为后台同步间隔
+func NewClockSync(client *MarketClient, interval time.Duration) *ClockSync {
+	return &ClockSync{
+		client:   client,
+		interval: interval,
+	}
+}
+
+// Now 返回按服务器时间修正后的当前时间
+func (c *ClockSync) Now() time.Time {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return time.Now().Add(c.offset)
+}
+
+// Offset 服务器时间与本地时间的偏差，服务器时间 = 本地时间 + Offset
+func (c *ClockSync) Offset() time.Duration {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.offset
+}
+
+// RTT 最近一次同步的请求往返延迟
+func (c *ClockSync) RTT() time.Duration {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.rtt
+}
+
+// LastSynced 最近一次同步成功的本地时间
+func (c *ClockSync) LastSynced() time.Time {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.synced
+}
+
+// Sync 立即与服务器对时一次
+func (c *ClockSync) Sync() error {
+	var bestOffset, bestRTT time.Duration
+	var lastErr error
+	success := false
+	for i := 0; i < clockSyncSamples; i++ {
+		start := time.Now()
+		timestamp, err := c.client.Timestamp()
+		end := time.Now()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		rtt := end.Sub(start)
+		// 假设请求往返耗时对称，服务器时间对应本地请求的中间时刻
+		server := time.Unix(0, timestamp*int64(time.Millisecond))
+		offset := server.Sub(start.Add(rtt / 2))
+		if !success || rtt < bestRTT {
+			bestOffset, bestRTT = offset, rtt
+			success = true
+		}
+	}
+	if !success {
+		return lastErr
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.offset, c.rtt, c.synced = bestOffset, bestRTT, time.Now()
+	c.client.cfg.Logger.Println("Clock synced, offset:", bestOffset, "rtt:", bestRTT)
+	return nil
+}
+
+// Start 立即对时一次并启动后台定期对时，首次对时失败时直接返回错误
+func (c *ClockSync) Start() error {
+	if c.interval <= 0 {
+		return fmt.Errorf("Clock sync interval must be positive")
+	}
+	if err := c.Sync(); err != nil {
+		return err
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.stop != nil {
+		return nil
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.syncLoop(c.stop, c.done)
+	return nil
+}
+
+// Stop 停止后台对时，保留最近一次的偏差
+func (c *ClockSync) Stop() {
+	c.m.Lock()
+	stop, done := c.stop, c.done
+	c.stop, c.done = nil, nil
+	c.m.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (c *ClockSync) syncLoop(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := c.Sync(); err != nil {
+				c.client.cfg.Logger.Println("Clock sync error:", err)
+			}
+		}
+	}
+}
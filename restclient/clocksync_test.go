@@ -0,0 +1,81 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+// skewedServer 返回比本地快 skew 的服务器时间，并记录签名请求中的 Timestamp
+type skewedServer struct {
+	skew       time.Duration
+	m          sync.Mutex
+	timestamps []string
+}
+
+func (server *skewedServer) handler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v1/common/timestamp" {
+		now := time.Now().Add(server.skew).UnixNano() / int64(time.Millisecond)
+		writeJSON(w, http.StatusOK, fmt.Sprintf(`{"status":"ok","data":%d}`, now))
+		return
+	}
+	server.m.Lock()
+	server.timestamps = append(server.timestamps, r.URL.Query().Get("Timestamp"))
+	server.m.Unlock()
+	writeJSON(w, http.StatusOK, `{"status":"ok","data":[]}`)
+}
+
+func TestClockSync(t *testing.T) {
+	server := &skewedServer{skew: time.Hour}
+	ts := newTestServer(server.handler)
+	defer ts.Close()
+	market, _ := NewMarketClient(testOptions(ts)...)
+	clock := NewClockSync(market, time.Hour)
+	if err := clock.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if offset := clock.Offset(); offset < time.Hour-time.Second || offset > time.Hour+time.Second {
+		t.Errorf("offset = %v, want about 1h", offset)
+	}
+	if clock.LastSynced().IsZero() {
+		t.Error("LastSynced not set")
+	}
+
+	// 签名使用修正后的时间
+	trade, _ := NewTradeClient("ak", "sk", append(testOptions(ts), config.WithClock(clock))...)
+	if _, err := trade.Accounts(); err != nil {
+		t.Fatal(err)
+	}
+	server.m.Lock()
+	defer server.m.Unlock()
+	if len(server.timestamps) != 1 {
+		t.Fatalf("timestamps = %v", server.timestamps)
+	}
+	signed, err := time.Parse("2006-01-02T15:04:05", server.timestamps[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := signed.Sub(time.Now().Add(time.Hour)); diff < -2*time.Second || diff > 2*time.Second {
+		t.Errorf("signed timestamp %v, want server time", signed)
+	}
+}
+
+func TestClockSyncStart(t *testing.T) {
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"status":"error","err-code":"base-system-error","err-msg":"unavailable"}`)
+	})
+	defer ts.Close()
+	market, _ := NewMarketClient(testOptions(ts)...)
+	if err := NewClockSync(market, 0).Start(); err == nil {
+		t.Error("want error for non-positive interval")
+	}
+	clock := NewClockSync(market, time.Hour)
+	if err := clock.Start(); err == nil {
+		t.Error("want error when first sync fails")
+	}
+	clock.Stop()
+}
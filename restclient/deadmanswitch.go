@@ -0,0 +1,106 @@
+package restclient
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CancelAllAfterResult 自动撤单倒计时设置结果
+type CancelAllAfterResult struct {
+	CurrentTime int64 `json:"currentTime"` // 当前服务器毫秒时间戳
+	TriggerTime int64 `json:"triggerTime"` // 触发撤单的毫秒时间戳，取消倒计时时为0
+}
+
+// CancelAllAfter 设置自动撤单倒计时，timeout 秒内未再次设置则撤销所有订单，
+// timeout 最小为5秒，为0时取消倒计时
+func (client *TradeClient) CancelAllAfter(timeout int) (*CancelAllAfterResult, error) {
+	var result CancelAllAfterResult
+	_, err := client.HandlePost("/v2/algo-orders/cancel-all-after", &result,
+		map[string]interface{}{"timeout": timeout})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeadManSwitch 自动撤单保护，启动后在后台定时续期撤单倒计时，
+// 进程退出或网络中断导致无法续期时，服务端会在倒计时结束后撤销所有订单
+type DeadManSwitch struct {
+	client   *TradeClient
+	timeout  time.Duration
+	interval time.Duration
+	onError  func(err error)
+	stop     chan struct{}
+	done     chan struct{}
+	m        sync.Mutex
+}
+
+// NewDeadManSwitch 创建自动撤单保护，timeout 为撤单倒计时，interval 为续期间隔，
+// onError 在续期失败时于新的goroutine中回调，可以在其中调用 Stop，可以为nil
+func (client *TradeClient) NewDeadManSwitch(timeout, interval time.Duration, onError func(err error)) (*DeadManSwitch, error) {
+	if timeout < 5*time.Second {
+		return nil, fmt.Errorf("Cancel all after timeout must be at least 5s")
+	}
+	if interval <= 0 || interval >= timeout {
+		return nil, fmt.Errorf("Renew interval must be positive and less than timeout")
+	}
+	return &DeadManSwitch{
+		client:   client,
+		timeout:  timeout,
+		interval: interval,
+		onError:  onError,
+	}, nil
+}
+
+// Start 设置撤单倒计时并启动后台续期，首次设置失败时直接返回错误
+func (s *DeadManSwitch) Start() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.stop != nil {
+		return fmt.Errorf("Dead man's switch already started")
+	}
+	if err := s.arm(); err != nil {
+		return err
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.renewLoop(s.stop, s.done)
+	return nil
+}
+
+// Stop 停止后台续期并取消撤单倒计时
+func (s *DeadManSwitch) Stop() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.stop == nil {
+		return nil
+	}
+	close(s.stop)
+	<-s.done
+	s.stop, s.done = nil, nil
+	_, err := s.client.CancelAllAfter(0)
+	return err
+}
+
+func (s *DeadManSwitch) arm() error {
+	_, err := s.client.CancelAllAfter(int(s.timeout / time.Second))
+	return err
+}
+
+func (s *DeadManSwitch) renewLoop(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			// 回调不阻塞续期，回调中调用 Stop 也不会等待自身
+			if err := s.arm(); err != nil && s.onError != nil {
+				go s.onError(err)
+			}
+		}
+	}
+}
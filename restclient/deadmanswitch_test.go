@@ -0,0 +1,109 @@
+package restclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// cancelAllAfterServer 记录每次设置的倒计时，fail 不为0时续期失败
+type cancelAllAfterServer struct {
+	m        sync.Mutex
+	timeouts []int
+	fail     bool
+}
+
+func (server *cancelAllAfterServer) handler(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Timeout int `json:"timeout"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	server.m.Lock()
+	defer server.m.Unlock()
+	server.timeouts = append(server.timeouts, body.Timeout)
+	if server.fail && body.Timeout != 0 {
+		writeJSON(w, http.StatusOK, `{"code":500,"message":"renew failed"}`)
+		return
+	}
+	writeJSON(w, http.StatusOK, `{"code":200,"data":{"currentTime":1,"triggerTime":2}}`)
+}
+
+func (server *cancelAllAfterServer) snapshot() []int {
+	server.m.Lock()
+	defer server.m.Unlock()
+	return append([]int(nil), server.timeouts...)
+}
+
+func TestDeadManSwitch(t *testing.T) {
+	server := &cancelAllAfterServer{}
+	ts := newTestServer(server.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	if _, err := client.NewDeadManSwitch(time.Second, 100*time.Millisecond, nil); err == nil {
+		t.Error("want error for timeout below 5s")
+	}
+	if _, err := client.NewDeadManSwitch(5*time.Second, 5*time.Second, nil); err == nil {
+		t.Error("want error for interval not less than timeout")
+	}
+
+	s, err := client.NewDeadManSwitch(5*time.Second, 20*time.Millisecond, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(); err == nil {
+		t.Error("want error when started twice")
+	}
+	time.Sleep(70 * time.Millisecond)
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	timeouts := server.snapshot()
+	if len(timeouts) < 3 {
+		t.Fatalf("timeouts = %v, want arm and renewals", timeouts)
+	}
+	for _, timeout := range timeouts[:len(timeouts)-1] {
+		if timeout != 5 {
+			t.Errorf("timeouts = %v, want 5 before Stop", timeouts)
+		}
+	}
+	if last := timeouts[len(timeouts)-1]; last != 0 {
+		t.Errorf("last timeout = %d, want 0 after Stop", last)
+	}
+}
+
+func TestDeadManSwitchStopFromOnError(t *testing.T) {
+	server := &cancelAllAfterServer{}
+	ts := newTestServer(server.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	var s *DeadManSwitch
+	stopped := make(chan error, 1)
+	s, err := client.NewDeadManSwitch(5*time.Second, 10*time.Millisecond, func(err error) {
+		select {
+		case stopped <- s.Stop():
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	server.m.Lock()
+	server.fail = true
+	server.m.Unlock()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop from onError deadlocked")
+	}
+}
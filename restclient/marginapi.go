@@ -0,0 +1,241 @@
+package restclient
+
+import (
+	"fmt"
+)
+
+// PlaceOrderRequest.Source 的取值，杠杆账户下单时需指定
+const (
+	SourceSpotAPI        = "spot-api"         // 现货
+	SourceMarginAPI      = "margin-api"       // 逐仓杠杆
+	SourceSuperMarginAPI = "super-margin-api" // 全仓杠杆
+)
+
+// LoanCurrency 币种借币信息
+type LoanCurrency struct {
+	Currency     string `json:"currency"`
+	InterestRate string `json:"interest-rate"`
+	MinLoanAmt   string `json:"min-loan-amt"`
+	MaxLoanAmt   string `json:"max-loan-amt"`
+	LoanableAmt  string `json:"loanable-amt"`
+	ActualRate   string `json:"actual-rate"`
+}
+
+// MarginLoanInfo 逐仓杠杆交易对借币信息
+type MarginLoanInfo struct {
+	Symbol     string         `json:"symbol"`
+	Currencies []LoanCurrency `json:"currencies"`
+}
+
+// MarginBalance 逐仓杠杆账户余额
+type MarginBalance struct {
+	ID       int64         `json:"id"`
+	Type     string        `json:"type"`
+	State    string        `json:"state"`
+	Symbol   string        `json:"symbol"`
+	FlPrice  string        `json:"fl-price"`
+	FlType   string        `json:"fl-type"`
+	RiskRate string        `json:"risk-rate"`
+	List     []BalanceItem `json:"list"`
+}
+
+// CrossMarginBalance 全仓杠杆账户余额
+type CrossMarginBalance struct {
+	ID             int64         `json:"id"`
+	Type           string        `json:"type"`
+	State          string        `json:"state"`
+	RiskRate       string        `json:"risk-rate"`
+	AcctBalanceSum string        `json:"acct-balance-sum"`
+	DebtBalanceSum string        `json:"debt-balance-sum"`
+	List           []BalanceItem `json:"list"`
+}
+
+// LoanOrder 借币订单
+type LoanOrder struct {
+	ID               int64  `json:"id"`
+	UserID           int64  `json:"user-id"`
+	AccountID        int64  `json:"account-id"`
+	Symbol           string `json:"symbol"` // 全仓杠杆借币订单无此字段
+	Currency         string `json:"currency"`
+	LoanAmount       string `json:"loan-amount"`
+	LoanBalance      string `json:"loan-balance"`
+	InterestRate     string `json:"interest-rate"`
+	InterestAmount   string `json:"interest-amount"`
+	InterestBalance  string `json:"interest-balance"`
+	State            string `json:"state"` // created、accrual、cleared、invalid
+	PaidPoint        string `json:"paid-point"`
+	PaidCoin         string `json:"paid-coin"`
+	DeductRate       string `json:"deduct-rate"`
+	DeductCurrency   string `json:"deduct-currency"`
+	DeductAmount     string `json:"deduct-amount"`
+	HourInterestRate string `json:"hour-interest-rate"`
+	DayInterestRate  string `json:"day-interest-rate"`
+	CreatedAt        int64  `json:"created-at"`
+	AccruedAt        int64  `json:"accrued-at"`
+	UpdatedAt        int64  `json:"updated-at"`
+}
+
+// LoanOrdersQuery 借币订单查询条件，逐仓杠杆查询时 Symbol 必填
+type LoanOrdersQuery struct {
+	Symbol    string // 仅逐仓杠杆
+	Currency  string // 仅全仓杠杆
+	States    []string
+	StartDate string // yyyy-mm-dd
+	EndDate   string // yyyy-mm-dd
+	From      int64
+	Direct    string // prev、next
+	Size      int
+	SubUID    int64
+}
+
+func (query *LoanOrdersQuery) params(cross bool) map[string]interface{} {
+	params := map[string]interface{}{}
+	if cross {
+		setString(params, "currency", query.Currency)
+		setStrings(params, "state", query.States)
+	} else {
+		setString(params, "symbol", query.Symbol)
+		setStrings(params, "states", query.States)
+	}
+	setString(params, "start-date", query.StartDate)
+	setString(params, "end-date", query.EndDate)
+	setInt(params, "from", query.From)
+	setString(params, "direct", query.Direct)
+	setInt(params, "size", int64(query.Size))
+	setInt(params, "sub-uid", query.SubUID)
+	return params
+}
+
+// Repayment 通用还币结果
+type Repayment struct {
+	RepayID   int64 `json:"repayId"`
+	RepayTime int64 `json:"repayTime"`
+}
+
+// MarginTransferIn 从现货账户划转至逐仓杠杆账户，返回划转ID
+func (client *TradeClient) MarginTransferIn(symbol, currency, amount string) (int64, error) {
+	return client.marginTransfer("/v1/dw/transfer-in/margin", symbol, currency, amount)
+}
+
+// MarginTransferOut 从逐仓杠杆账户划转至现货账户，返回划转ID
+func (client *TradeClient) MarginTransferOut(symbol, currency, amount string) (int64, error) {
+	return client.marginTransfer("/v1/dw/transfer-out/margin", symbol, currency, amount)
+}
+
+func (client *TradeClient) marginTransfer(path, symbol, currency, amount string) (int64, error) {
+	params := map[string]interface{}{"currency": currency, "amount": amount}
+	setString(params, "symbol", symbol)
+	var id int64
+	_, err := client.HandlePost(path, &id, params)
+	return id, err
+}
+
+// MarginLoanInfo 查询逐仓杠杆借币币息率及额度，symbols 为空时返回所有交易对
+func (client *TradeClient) MarginLoanInfo(symbols ...string) ([]MarginLoanInfo, error) {
+	params := map[string]interface{}{}
+	setStrings(params, "symbols", symbols)
+	var infos []MarginLoanInfo
+	_, err := client.HandleGet("/v1/margin/loan-info", &infos, params)
+	return infos, err
+}
+
+// MarginLoan 逐仓杠杆申请借币，返回借币订单ID
+func (client *TradeClient) MarginLoan(symbol, currency, amount string) (int64, error) {
+	var id int64
+	_, err := client.HandlePost("/v1/margin/orders", &id, map[string]interface{}{
+		"symbol":   symbol,
+		"currency": currency,
+		"amount":   amount,
+	})
+	return id, err
+}
+
+// MarginRepay 逐仓杠杆归还借币，返回借币订单ID
+func (client *TradeClient) MarginRepay(loanOrderID int64, amount string) (int64, error) {
+	var id int64
+	_, err := client.HandlePost(fmt.Sprintf("/v1/margin/orders/%d/repay", loanOrderID), &id,
+		map[string]interface{}{"amount": amount})
+	return id, err
+}
+
+// MarginLoanOrders 查询逐仓杠杆借币订单
+func (client *TradeClient) MarginLoanOrders(query *LoanOrdersQuery) ([]LoanOrder, error) {
+	var orders []LoanOrder
+	_, err := client.HandleGet("/v1/margin/loan-orders", &orders, query.params(false))
+	return orders, err
+}
+
+// MarginBalance 查询逐仓杠杆账户余额，symbol 为空时返回所有交易对
+func (client *TradeClient) MarginBalance(symbol string, subUID int64) ([]MarginBalance, error) {
+	params := map[string]interface{}{}
+	setString(params, "symbol", symbol)
+	setInt(params, "sub-uid", subUID)
+	var balances []MarginBalance
+	_, err := client.HandleGet("/v1/margin/accounts/balance", &balances, params)
+	return balances, err
+}
+
+// CrossMarginTransferIn 从现货账户划转至全仓杠杆账户，返回划转ID
+func (client *TradeClient) CrossMarginTransferIn(currency, amount string) (int64, error) {
+	return client.marginTransfer("/v1/cross-margin/transfer-in", "", currency, amount)
+}
+
+// CrossMarginTransferOut 从全仓杠杆账户划转至现货账户，返回划转ID
+func (client *TradeClient) CrossMarginTransferOut(currency, amount string) (int64, error) {
+	return client.marginTransfer("/v1/cross-margin/transfer-out", "", currency, amount)
+}
+
+// CrossMarginLoanInfo 查询全仓杠杆借币币息率及额度
+func (client *TradeClient) CrossMarginLoanInfo() ([]LoanCurrency, error) {
+	var infos []LoanCurrency
+	_, err := client.HandleGet("/v1/cross-margin/loan-info", &infos)
+	return infos, err
+}
+
+// CrossMarginLoan 全仓杠杆申请借币，返回借币订单ID
+func (client *TradeClient) CrossMarginLoan(currency, amount string) (int64, error) {
+	var id int64
+	_, err := client.HandlePost("/v1/cross-margin/orders", &id, map[string]interface{}{
+		"currency": currency,
+		"amount":   amount,
+	})
+	return id, err
+}
+
+// CrossMarginRepay 全仓杠杆归还借币
+func (client *TradeClient) CrossMarginRepay(loanOrderID int64, amount string) error {
+	_, err := client.Post(fmt.Sprintf("/v1/cross-margin/orders/%d/repay", loanOrderID),
+		map[string]interface{}{"amount": amount})
+	return err
+}
+
+// CrossMarginLoanOrders 查询全仓杠杆借币订单
+func (client *TradeClient) CrossMarginLoanOrders(query *LoanOrdersQuery) ([]LoanOrder, error) {
+	var orders []LoanOrder
+	_, err := client.HandleGet("/v1/cross-margin/loan-orders", &orders, query.params(true))
+	return orders, err
+}
+
+// CrossMarginBalance 查询全仓杠杆账户余额
+func (client *TradeClient) CrossMarginBalance(subUID int64) (*CrossMarginBalance, error) {
+	params := map[string]interface{}{}
+	setInt(params, "sub-uid", subUID)
+	var balance CrossMarginBalance
+	if _, err := client.HandleGet("/v1/cross-margin/accounts/balance", &balance, params); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// Repay 通用还币接口，按账户归还借币，transactID 为空时按借币时间顺序归还
+func (client *TradeClient) Repay(accountID int64, currency, amount, transactID string) ([]Repayment, error) {
+	params := map[string]interface{}{
+		"accountId": fmt.Sprint(accountID),
+		"currency":  currency,
+		"amount":    amount,
+	}
+	setString(params, "transactId", transactID)
+	var repayments []Repayment
+	_, err := client.HandlePost("/v2/account/repayment", &repayments, params)
+	return repayments, err
+}
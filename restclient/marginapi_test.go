@@ -0,0 +1,86 @@
+package restclient
+
+import (
+	"testing"
+)
+
+func TestMarginTransfer(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"POST /v1/dw/transfer-in/margin":     `{"status":"ok","data":1000}`,
+		"POST /v1/cross-margin/transfer-out": `{"status":"ok","data":1001}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+
+	id, err := client.MarginTransferIn("btcusdt", "usdt", "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, body := api.request(); body["symbol"] != "btcusdt" || body["currency"] != "usdt" || id != 1000 {
+		t.Errorf("body = %v, id = %d", body, id)
+	}
+
+	id, err = client.CrossMarginTransferOut("usdt", "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, body := api.request(); body["symbol"] != nil || body["amount"] != "5" || id != 1001 {
+		t.Errorf("body = %v, id = %d", body, id)
+	}
+}
+
+func TestLoanOrdersQuery(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"GET /v1/margin/loan-orders":       `{"status":"ok","data":[{"id":1,"symbol":"btcusdt","currency":"usdt","loan-amount":"100","state":"accrual"}]}`,
+		"GET /v1/cross-margin/loan-orders": `{"status":"ok","data":[{"id":2,"currency":"usdt","state":"cleared"}]}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	query := &LoanOrdersQuery{Symbol: "btcusdt", Currency: "usdt", States: []string{"accrual", "cleared"}, Size: 10}
+
+	orders, err := client.MarginLoanOrders(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, params, _ := api.request()
+	if params.Get("symbol") != "btcusdt" || params.Get("states") != "accrual,cleared" || params.Get("currency") != "" {
+		t.Errorf("isolated query = %v", params)
+	}
+	if len(orders) != 1 || orders[0].LoanAmount != "100" {
+		t.Errorf("orders = %+v", orders)
+	}
+
+	// 全仓杠杆使用 currency 及 state 参数
+	orders, err = client.CrossMarginLoanOrders(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, params, _ = api.request()
+	if params.Get("currency") != "usdt" || params.Get("state") != "accrual,cleared" || params.Get("symbol") != "" {
+		t.Errorf("cross query = %v", params)
+	}
+	if len(orders) != 1 || orders[0].State != "cleared" {
+		t.Errorf("orders = %+v", orders)
+	}
+}
+
+func TestRepay(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"POST /v2/account/repayment": `{"code":200,"data":[{"repayId":1338,"repayTime":1584695426136}]}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	repayments, err := client.Repay(100009, "usdt", "1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, body := api.request(); body["accountId"] != "100009" || body["transactId"] != nil {
+		t.Errorf("body = %v", body)
+	}
+	if len(repayments) != 1 || repayments[0].RepayID != 1338 {
+		t.Errorf("repayments = %+v", repayments)
+	}
+}
@@ -0,0 +1,207 @@
+package restclient
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/feeeei/huobiapi-go/utils"
+)
+
+// KlinePeriod K线周期
+type KlinePeriod string
+
+const (
+	Period1Min  KlinePeriod = "1min"
+	Period5Min  KlinePeriod = "5min"
+	Period15Min KlinePeriod = "15min"
+	Period30Min KlinePeriod = "30min"
+	Period60Min KlinePeriod = "60min"
+	Period4Hour KlinePeriod = "4hour"
+	Period1Day  KlinePeriod = "1day"
+	Period1Week KlinePeriod = "1week"
+	Period1Mon  KlinePeriod = "1mon"
+	Period1Year KlinePeriod = "1year"
+)
+
+// DepthType 深度合并类型，step0 为不合并
+type DepthType string
+
+const (
+	DepthStep0 DepthType = "step0"
+	DepthStep1 DepthType = "step1"
+	DepthStep2 DepthType = "step2"
+	DepthStep3 DepthType = "step3"
+	DepthStep4 DepthType = "step4"
+	DepthStep5 DepthType = "step5"
+)
+
+// Kline K线数据
+type Kline struct {
+	ID     int64   `json:"id"`
+	Amount float64 `json:"amount"`
+	Count  int64   `json:"count"`
+	Open   float64 `json:"open"`
+	Close  float64 `json:"close"`
+	Low    float64 `json:"low"`
+	High   float64 `json:"high"`
+	Vol    float64 `json:"vol"`
+}
+
+// PriceLevel 盘口档位，对应接口中的 [price, amount]
+type PriceLevel struct {
+	Price  float64
+	Amount float64
+}
+
+// UnmarshalJSON 解析 [price, amount] 格式数据
+func (level *PriceLevel) UnmarshalJSON(b []byte) error {
+	var pair []float64
+	if err := json.Unmarshal(b, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("Invalid price level %s", string(b))
+	}
+	level.Price, level.Amount = pair[0], pair[1]
+	return nil
+}
+
+// Depth 市场深度
+type Depth struct {
+	Ts      int64        `json:"ts"`
+	Version int64        `json:"version"`
+	Bids    []PriceLevel `json:"bids"`
+	Asks    []PriceLevel `json:"asks"`
+}
+
+// MarketDetail 最近24小时行情数据
+type MarketDetail struct {
+	ID      int64   `json:"id"`
+	Version int64   `json:"version"`
+	Amount  float64 `json:"amount"`
+	Count   int64   `json:"count"`
+	Open    float64 `json:"open"`
+	Close   float64 `json:"close"`
+	Low     float64 `json:"low"`
+	High    float64 `json:"high"`
+	Vol     float64 `json:"vol"`
+}
+
+// MergedDetail 聚合行情，在24小时行情基础上增加买一卖一
+type MergedDetail struct {
+	MarketDetail
+	Bid PriceLevel `json:"bid"`
+	Ask PriceLevel `json:"ask"`
+}
+
+// Ticker 所有交易对的最新行情
+type Ticker struct {
+	Symbol  string  `json:"symbol"`
+	Open    float64 `json:"open"`
+	High    float64 `json:"high"`
+	Low     float64 `json:"low"`
+	Close   float64 `json:"close"`
+	Amount  float64 `json:"amount"`
+	Vol     float64 `json:"vol"`
+	Count   int64   `json:"count"`
+	Bid     float64 `json:"bid"`
+	BidSize float64 `json:"bidSize"`
+	Ask     float64 `json:"ask"`
+	AskSize float64 `json:"askSize"`
+}
+
+// Trade 成交明细
+type Trade struct {
+	ID        json.Number `json:"id"`
+	TradeID   int64       `json:"trade-id"`
+	Price     float64     `json:"price"`
+	Amount    float64     `json:"amount"`
+	Direction string      `json:"direction"`
+	Ts        int64       `json:"ts"`
+}
+
+// TradeTick 一组成交明细
+type TradeTick struct {
+	ID   int64   `json:"id"`
+	Ts   int64   `json:"ts"`
+	Data []Trade `json:"data"`
+}
+
+// Klines 获取K线数据，size 范围 [1, 2000]，为0时使用服务端默认值
+func (client *MarketClient) Klines(symbol string, period KlinePeriod, size int) ([]Kline, error) {
+	params := map[string]interface{}{"symbol": symbol, "period": string(period)}
+	if size > 0 {
+		params["size"] = size
+	}
+	var klines []Kline
+	_, err := client.HandleGet("/market/history/kline", &klines, params)
+	return klines, err
+}
+
+// Depth 获取市场深度，depth 可选 5、10、20，不传时返回150档
+func (client *MarketClient) Depth(symbol string, depthType DepthType, depth ...int) (*Depth, error) {
+	params := map[string]interface{}{"symbol": symbol, "type": string(depthType)}
+	if len(depth) > 0 && depth[0] > 0 {
+		params["depth"] = depth[0]
+	}
+	var d Depth
+	if err := client.handleGetTick("/market/depth", &d, params); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// MergedDetail 获取聚合行情
+func (client *MarketClient) MergedDetail(symbol string) (*MergedDetail, error) {
+	var detail MergedDetail
+	if err := client.handleGetTick("/market/detail/merged", &detail, map[string]interface{}{"symbol": symbol}); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// Detail 获取最近24小时行情数据
+func (client *MarketClient) Detail(symbol string) (*MarketDetail, error) {
+	var detail MarketDetail
+	if err := client.handleGetTick("/market/detail", &detail, map[string]interface{}{"symbol": symbol}); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// Tickers 获取所有交易对的最新行情
+func (client *MarketClient) Tickers() ([]Ticker, error) {
+	var tickers []Ticker
+	_, err := client.HandleGet("/market/tickers", &tickers)
+	return tickers, err
+}
+
+// LatestTrade 获取最近一笔成交
+func (client *MarketClient) LatestTrade(symbol string) (*TradeTick, error) {
+	var tick TradeTick
+	if err := client.handleGetTick("/market/trade", &tick, map[string]interface{}{"symbol": symbol}); err != nil {
+		return nil, err
+	}
+	return &tick, nil
+}
+
+// HistoryTrades 获取最近的成交记录，size 范围 [1, 2000]，为0时使用服务端默认值
+func (client *MarketClient) HistoryTrades(symbol string, size int) ([]TradeTick, error) {
+	params := map[string]interface{}{"symbol": symbol}
+	if size > 0 {
+		params["size"] = size
+	}
+	var ticks []TradeTick
+	_, err := client.HandleGet("/market/history/trade", &ticks, params)
+	return ticks, err
+}
+
+// handleGetTick 将Response中的tick字段解析到obj中
+func (client *MarketClient) handleGetTick(path string, obj interface{}, params map[string]interface{}) error {
+	resp, err := client.Get(path, params)
+	if err != nil {
+		return err
+	}
+	_, err = utils.ParseField2Obj(resp, "tick", obj)
+	return err
+}
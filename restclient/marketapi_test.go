@@ -0,0 +1,84 @@
+package restclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/feeeei/huobiapi-go/apierror"
+)
+
+func TestKlines(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"GET /market/history/kline": `{"status":"ok","ch":"market.btcusdt.kline.1min","data":[{"id":1629769200,"open":49000.5,"close":49100,"low":48900,"high":49200,"amount":12.5,"vol":613000,"count":321}]}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewMarketClient(testOptions(ts)...)
+	klines, err := client.Klines("btcusdt", Period1Min, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, query, _ := api.request()
+	if query.Get("symbol") != "btcusdt" || query.Get("period") != "1min" || query.Get("size") != "1" {
+		t.Errorf("query = %v", query)
+	}
+	if len(klines) != 1 || klines[0].Open != 49000.5 || klines[0].Count != 321 {
+		t.Errorf("klines = %+v", klines)
+	}
+
+	if _, err := client.Klines("btcusdt", Period1Day, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, query, _ := api.request(); query.Get("size") != "" {
+		t.Errorf("size = %q, want omitted", query.Get("size"))
+	}
+}
+
+func TestDepth(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"GET /market/depth":         `{"status":"ok","tick":{"ts":1,"version":2,"bids":[[49000,0.5],[48999,1]],"asks":[[49001,0.25]]}}`,
+		"GET /market/detail/merged": `{"status":"ok","tick":{"id":1,"close":49000,"bid":[48999,1],"ask":[49001,2]}}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewMarketClient(testOptions(ts)...)
+	depth, err := client.Depth("btcusdt", DepthStep0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, query, _ := api.request(); query.Get("type") != "step0" || query.Get("depth") != "5" {
+		t.Errorf("query = %v", query)
+	}
+	if len(depth.Bids) != 2 || depth.Bids[1] != (PriceLevel{Price: 48999, Amount: 1}) || depth.Asks[0].Amount != 0.25 {
+		t.Errorf("depth = %+v", depth)
+	}
+
+	detail, err := client.MergedDetail("btcusdt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Close != 49000 || detail.Bid.Price != 48999 || detail.Ask.Amount != 2 {
+		t.Errorf("detail = %+v", detail)
+	}
+}
+
+func TestPriceLevelInvalid(t *testing.T) {
+	var level PriceLevel
+	if err := level.UnmarshalJSON([]byte(`[1,2,3]`)); err == nil {
+		t.Error("want error for price level with 3 fields")
+	}
+}
+
+func TestMarketAPIError(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"GET /market/trade": `{"status":"error","err-code":"invalid-parameter","err-msg":"invalid symbol"}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewMarketClient(testOptions(ts)...)
+	_, err := client.LatestTrade("nope")
+	var apiErr *apierror.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrCode != "invalid-parameter" {
+		t.Errorf("err = %v, want APIError invalid-parameter", err)
+	}
+}
@@ -1,6 +1,7 @@
 package restclient
 
 import (
+	"context"
 	"net/url"
 
 	"github.com/feeeei/huobiapi-go/utils"
@@ -11,17 +12,43 @@ import (
 
 type MarketClient struct {
 	Endpoint *url.URL
+	cfg      *config.Config
+	ctx      context.Context
 }
 
 // NewMarketClient REST格式行情Client
-func NewMarketClient() (*MarketClient, error) {
+func NewMarketClient(options ...config.Option) (*MarketClient, error) {
+	cfg := config.New(options...)
+	if err := cfg.HTTPProxyErr(); err != nil {
+		return nil, err
+	}
 	return &MarketClient{
-		Endpoint: config.HuobiRestEndpoint,
+		Endpoint: cfg.RestEndpoint,
+		cfg:      cfg,
 	}, nil
 }
 
+// WithContext 返回绑定ctx的Client副本，副本上的所有请求（包括类型化接口）都受ctx控制
+func (client *MarketClient) WithContext(ctx context.Context) *MarketClient {
+	c := *client
+	c.ctx = ctx
+	return &c
+}
+
+func (client *MarketClient) context() context.Context {
+	if client.ctx == nil {
+		return context.Background()
+	}
+	return client.ctx
+}
+
 // Get Get同步请求
 func (client *MarketClient) Get(path string, params ...map[string]interface{}) (*simplejson.Json, error) {
+	return client.GetContext(client.context(), path, params...)
+}
+
+// GetContext 可取消的Get同步请求
+func (client *MarketClient) GetContext(ctx context.Context, path string, params ...map[string]interface{}) (*simplejson.Json, error) {
 	if err := isValidParams(params); err != nil {
 		return nil, err
 	}
@@ -31,15 +58,20 @@ func (client *MarketClient) Get(path string, params ...map[string]interface{}) (
 		p = params[0]
 	}
 	url := client.Endpoint.String() + path
-	return request("GET", url, p)
+	return request(ctx, client.cfg, "GET", url, p)
 }
 
 // HandleGet 将Response解析到obj中
 func (client *MarketClient) HandleGet(path string, obj interface{}, params ...map[string]interface{}) (*simplejson.Json, error) {
+	return client.HandleGetContext(client.context(), path, obj, params...)
+}
+
+// HandleGetContext 可取消的HandleGet
+func (client *MarketClient) HandleGetContext(ctx context.Context, path string, obj interface{}, params ...map[string]interface{}) (*simplejson.Json, error) {
 	if err := utils.CheckPointer(obj); err != nil {
 		return nil, err
 	}
-	resp, err := client.Get(path, params...)
+	resp, err := client.GetContext(ctx, path, params...)
 	if err != nil {
 		return resp, err
 	}
@@ -48,6 +80,11 @@ func (client *MarketClient) HandleGet(path string, obj interface{}, params ...ma
 
 // Post Post同步请求
 func (client *MarketClient) Post(path string, params ...map[string]interface{}) (*simplejson.Json, error) {
+	return client.PostContext(client.context(), path, params...)
+}
+
+// PostContext 可取消的Post同步请求
+func (client *MarketClient) PostContext(ctx context.Context, path string, params ...map[string]interface{}) (*simplejson.Json, error) {
 	if err := isValidParams(params); err != nil {
 		return nil, err
 	}
@@ -57,15 +94,20 @@ func (client *MarketClient) Post(path string, params ...map[string]interface{})
 	if params != nil {
 		body = params[0]
 	}
-	return request("POST", url, body)
+	return request(ctx, client.cfg, "POST", url, body)
 }
 
 // HandlePost 将Response解析到obj中
 func (client *MarketClient) HandlePost(path string, obj interface{}, params ...map[string]interface{}) (*simplejson.Json, error) {
+	return client.HandlePostContext(client.context(), path, obj, params...)
+}
+
+// HandlePostContext 可取消的HandlePost
+func (client *MarketClient) HandlePostContext(ctx context.Context, path string, obj interface{}, params ...map[string]interface{}) (*simplejson.Json, error) {
 	if err := utils.CheckPointer(obj); err != nil {
 		return nil, err
 	}
-	resp, err := client.Post(path, params...)
+	resp, err := client.PostContext(ctx, path, params...)
 	if err != nil {
 		return resp, err
 	}
@@ -0,0 +1,46 @@
+package restclient
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestProxyNotApplied(t *testing.T) {
+	proxy, _ := url.Parse("http://127.0.0.1:8080")
+	custom := roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, errors.New("unused") })
+	options := []config.Option{config.WithHTTPClient(&http.Client{Transport: custom}), config.WithProxy(proxy)}
+	if _, err := NewMarketClient(options...); !errors.Is(err, config.ErrProxyNotApplied) {
+		t.Errorf("NewMarketClient = %v, want ErrProxyNotApplied", err)
+	}
+	if _, err := NewTradeClient("ak", "sk", options...); !errors.Is(err, config.ErrProxyNotApplied) {
+		t.Errorf("NewTradeClient = %v, want ErrProxyNotApplied", err)
+	}
+}
+
+func TestClientsIsolated(t *testing.T) {
+	first := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"status":"ok","data":1}`)
+	})
+	defer first.Close()
+	second := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, `{"status":"ok","data":2}`)
+	})
+	defer second.Close()
+	firstClient, _ := NewMarketClient(testOptions(first)...)
+	secondClient, _ := NewMarketClient(testOptions(second)...)
+	for client, want := range map[*MarketClient]int64{firstClient: 1, secondClient: 2} {
+		if got, err := client.Timestamp(); err != nil || got != want {
+			t.Errorf("Timestamp = %d, %v, want %d", got, err, want)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package restclient
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/feeeei/huobiapi-go/apierror"
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+// OrderType 订单类型
+type OrderType string
+
+const (
+	OrderTypeBuyMarket        OrderType = "buy-market"
+	OrderTypeSellMarket       OrderType = "sell-market"
+	OrderTypeBuyLimit         OrderType = "buy-limit"
+	OrderTypeSellLimit        OrderType = "sell-limit"
+	OrderTypeBuyIOC           OrderType = "buy-ioc"
+	OrderTypeSellIOC          OrderType = "sell-ioc"
+	OrderTypeBuyLimitMaker    OrderType = "buy-limit-maker"
+	OrderTypeSellLimitMaker   OrderType = "sell-limit-maker"
+	OrderTypeBuyStopLimit     OrderType = "buy-stop-limit"
+	OrderTypeSellStopLimit    OrderType = "sell-stop-limit"
+	OrderTypeBuyLimitFOK      OrderType = "buy-limit-fok"
+	OrderTypeSellLimitFOK     OrderType = "sell-limit-fok"
+	OrderTypeBuyStopLimitFOK  OrderType = "buy-stop-limit-fok"
+	OrderTypeSellStopLimitFOK OrderType = "sell-stop-limit-fok"
+)
+
+// IsBuy 是否为买单
+func (t OrderType) IsBuy() bool {
+	return strings.HasPrefix(string(t), "buy-")
+}
+
+// IsStop 是否为止盈止损单
+func (t OrderType) IsStop() bool {
+	return strings.Contains(string(t), "-stop-")
+}
+
+// OrderState 订单状态
+type OrderState string
+
+const (
+	OrderStateCreated         OrderState = "created"
+	OrderStateSubmitted       OrderState = "submitted"
+	OrderStatePartialFilled   OrderState = "partial-filled"
+	OrderStateFilled          OrderState = "filled"
+	OrderStatePartialCanceled OrderState = "partial-canceled"
+	OrderStateCanceling       OrderState = "canceling"
+	OrderStateCanceled        OrderState = "canceled"
+)
+
+// IsFinal 订单是否已处于终态
+func (s OrderState) IsFinal() bool {
+	return s == OrderStateFilled || s == OrderStatePartialCanceled || s == OrderStateCanceled
+}
+
+// PlaceOrderRequest 下单参数，数量与价格使用字符串以避免精度丢失
+type PlaceOrderRequest struct {
+	AccountID     int64
+	Symbol        string
+	Type          OrderType
+	Amount        string
+	Price         string
+	Source        string // spot-api(默认)、margin-api、super-margin-api、c2c-margin-api
+	ClientOrderID string
+	StopPrice     string
+	Operator      string // 止盈止损单触发条件 gte、lte
+}
+
+func (req *PlaceOrderRequest) params() map[string]interface{} {
+	params := map[string]interface{}{
+		"account-id": fmt.Sprint(req.AccountID),
+		"symbol":     req.Symbol,
+		"type":       string(req.Type),
+		"amount":     req.Amount,
+	}
+	setString(params, "price", req.Price)
+	setString(params, "source", req.Source)
+	setString(params, "client-order-id", req.ClientOrderID)
+	setString(params, "stop-price", req.StopPrice)
+	setString(params, "operator", req.Operator)
+	return params
+}
+
+// Order 订单详情
+type Order struct {
+	ID               int64      `json:"id"`
+	ClientOrderID    string     `json:"client-order-id"`
+	Symbol           string     `json:"symbol"`
+	AccountID        int64      `json:"account-id"`
+	Amount           string     `json:"amount"`
+	Price            string     `json:"price"`
+	Type             OrderType  `json:"type"`
+	State            OrderState `json:"state"`
+	Source           string     `json:"source"`
+	StopPrice        string     `json:"stop-price"`
+	Operator         string     `json:"operator"`
+	FilledAmount     string     `json:"field-amount"`
+	FilledCashAmount string     `json:"field-cash-amount"`
+	FilledFees       string     `json:"field-fees"`
+	CreatedAt        int64      `json:"created-at"`
+	FinishedAt       int64      `json:"finished-at"`
+	CanceledAt       int64      `json:"canceled-at"`
+}
+
+// UnmarshalJSON 兼容未成交订单接口中 filled-* 格式的成交字段
+func (order *Order) UnmarshalJSON(b []byte) error {
+	type rawOrder Order
+	var raw struct {
+		rawOrder
+		FilledAmount     string `json:"filled-amount"`
+		FilledCashAmount string `json:"filled-cash-amount"`
+		FilledFees       string `json:"filled-fees"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*order = Order(raw.rawOrder)
+	if order.FilledAmount == "" {
+		order.FilledAmount = raw.FilledAmount
+	}
+	if order.FilledCashAmount == "" {
+		order.FilledCashAmount = raw.FilledCashAmount
+	}
+	if order.FilledFees == "" {
+		order.FilledFees = raw.FilledFees
+	}
+	return nil
+}
+
+// MatchResult 成交明细
+type MatchResult struct {
+	ID                int64     `json:"id"`
+	OrderID           int64     `json:"order-id"`
+	MatchID           int64     `json:"match-id"`
+	TradeID           int64     `json:"trade-id"`
+	Symbol            string    `json:"symbol"`
+	Type              OrderType `json:"type"`
+	Source            string    `json:"source"`
+	Role              string    `json:"role"`
+	Price             string    `json:"price"`
+	FilledAmount      string    `json:"filled-amount"`
+	FilledFees        string    `json:"filled-fees"`
+	FeeCurrency       string    `json:"fee-currency"`
+	FilledPoints      string    `json:"filled-points"`
+	FeeDeductCurrency string    `json:"fee-deduct-currency"`
+	FeeDeductState    string    `json:"fee-deduct-state"`
+	CreatedAt         int64     `json:"created-at"`
+}
+
+// OpenOrdersQuery 未成交订单查询条件
+type OpenOrdersQuery struct {
+	AccountID int64
+	Symbol    string
+	Side      string // buy、sell
+	From      int64
+	Direct    string // prev、next
+	Size      int
+}
+
+func (query *OpenOrdersQuery) params() map[string]interface{} {
+	params := map[string]interface{}{}
+	setInt(params, "account-id", query.AccountID)
+	setString(params, "symbol", query.Symbol)
+	setString(params, "side", query.Side)
+	setInt(params, "from", query.From)
+	setString(params, "direct", query.Direct)
+	setInt(params, "size", int64(query.Size))
+	return params
+}
+
+// OrderHistoryQuery 历史订单查询条件，Symbol 与 States 必填
+type OrderHistoryQuery struct {
+	Symbol    string
+	Types     []OrderType
+	States    []OrderState
+	StartTime int64 // 毫秒时间戳
+	EndTime   int64 // 毫秒时间戳
+	From      int64
+	Direct    string // prev、next
+	Size      int
+}
+
+func (query *OrderHistoryQuery) params() map[string]interface{} {
+	params := map[string]interface{}{"symbol": query.Symbol}
+	types := make([]string, len(query.Types))
+	for i, t := range query.Types {
+		types[i] = string(t)
+	}
+	states := make([]string, len(query.States))
+	for i, s := range query.States {
+		states[i] = string(s)
+	}
+	setStrings(params, "types", types)
+	setStrings(params, "states", states)
+	setInt(params, "start-time", query.StartTime)
+	setInt(params, "end-time", query.EndTime)
+	setInt(params, "from", query.From)
+	setString(params, "direct", query.Direct)
+	setInt(params, "size", int64(query.Size))
+	return params
+}
+
+// MatchResultsQuery 成交明细查询条件，Symbol 必填
+type MatchResultsQuery struct {
+	Symbol    string
+	Types     []OrderType
+	StartTime int64 // 毫秒时间戳
+	EndTime   int64 // 毫秒时间戳
+	From      int64
+	Direct    string // prev、next
+	Size      int
+}
+
+func (query *MatchResultsQuery) params() map[string]interface{} {
+	params := map[string]interface{}{"symbol": query.Symbol}
+	types := make([]string, len(query.Types))
+	for i, t := range query.Types {
+		types[i] = string(t)
+	}
+	setStrings(params, "types", types)
+	setInt(params, "start-time", query.StartTime)
+	setInt(params, "end-time", query.EndTime)
+	setInt(params, "from", query.From)
+	setString(params, "direct", query.Direct)
+	setInt(params, "size", int64(query.Size))
+	return params
+}
+
+// PlaceOrder 下单，返回订单ID。
+// 配置了重试策略时，未指定 ClientOrderID 会自动生成；
+// 网络错误或5xx等无法确认是否下单成功时，等待后先按 client-order-id 查询，确认未下单后才会重新提交，避免重复下单
+func (client *TradeClient) PlaceOrder(req *PlaceOrderRequest) (int64, error) {
+	policy := client.cfg.Retry
+	if policy == nil {
+		return client.placeOrder(req)
+	}
+
+	r := *req
+	if r.ClientOrderID == "" {
+		r.ClientOrderID = newClientOrderID()
+	}
+	for attempt := 1; ; attempt++ {
+		id, err := client.placeOrder(&r)
+		if err == nil {
+			return id, nil
+		}
+		// 重新提交被拒绝为重复的 client-order-id，说明之前的请求已经下单
+		if attempt > 1 && isDuplicateClientOrderID(err) {
+			return client.findPlacedOrder(policy, r.ClientOrderID, err)
+		}
+		if !isAmbiguous(err) {
+			return 0, err
+		}
+		client.cfg.Logger.Println("Place order", r.ClientOrderID, "result unknown:", err)
+		// 先等待再查询，避免处理中的订单被当作未下单而重复提交
+		if err := sleepBackoff(client.context(), policy, attempt); err != nil {
+			return 0, err
+		}
+		order, qerr := client.GetOrderByClientOrderID(r.ClientOrderID)
+		if qerr == nil {
+			return order.ID, nil
+		}
+		if !apierror.IsOrderNotFound(qerr) || !policy.CanRetry(attempt) {
+			return 0, err
+		}
+	}
+}
+
+// findPlacedOrder 按 client-order-id 查询已提交的订单，查询不到时按重试策略等待后再次查询，
+// 最终仍查询不到时返回 err
+func (client *TradeClient) findPlacedOrder(policy *config.RetryPolicy, clientOrderID string, err error) (int64, error) {
+	for attempt := 1; ; attempt++ {
+		order, qerr := client.GetOrderByClientOrderID(clientOrderID)
+		if qerr == nil {
+			return order.ID, nil
+		}
+		if !apierror.IsOrderNotFound(qerr) || !policy.CanRetry(attempt) {
+			return 0, err
+		}
+		if err := sleepBackoff(client.context(), policy, attempt); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (client *TradeClient) placeOrder(req *PlaceOrderRequest) (int64, error) {
+	var id string
+	if _, err := client.HandlePost("/v1/order/orders/place", &id, req.params()); err != nil {
+		return 0, err
+	}
+	return parseID(id)
+}
+
+// newClientOrderID 生成随机 client-order-id
+func newClientOrderID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// CancelOrder 根据订单ID撤单
+func (client *TradeClient) CancelOrder(orderID int64) error {
+	_, err := client.Post(fmt.Sprintf("/v1/order/orders/%d/submitcancel", orderID))
+	return err
+}
+
+// CancelByClientOrderID 根据 client-order-id 撤单，返回订单状态码
+func (client *TradeClient) CancelByClientOrderID(clientOrderID string) (int, error) {
+	var state int
+	_, err := client.HandlePost("/v1/order/orders/submitCancelClientOrder", &state,
+		map[string]interface{}{"client-order-id": clientOrderID})
+	return state, err
+}
+
+// GetOrder 根据订单ID查询订单详情
+func (client *TradeClient) GetOrder(orderID int64) (*Order, error) {
+	var order Order
+	if _, err := client.HandleGet(fmt.Sprintf("/v1/order/orders/%d", orderID), &order); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetOrderByClientOrderID 根据 client-order-id 查询订单详情
+func (client *TradeClient) GetOrderByClientOrderID(clientOrderID string) (*Order, error) {
+	var order Order
+	_, err := client.HandleGet("/v1/order/orders/getClientOrder", &order,
+		map[string]interface{}{"clientOrderId": clientOrderID})
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// OpenOrders 查询当前未成交订单
+func (client *TradeClient) OpenOrders(query *OpenOrdersQuery) ([]Order, error) {
+	var orders []Order
+	_, err := client.HandleGet("/v1/order/openOrders", &orders, query.params())
+	return orders, err
+}
+
+// OrderHistory 搜索历史订单
+func (client *TradeClient) OrderHistory(query *OrderHistoryQuery) ([]Order, error) {
+	var orders []Order
+	_, err := client.HandleGet("/v1/order/orders", &orders, query.params())
+	return orders, err
+}
+
+// MatchResults 查询当前及历史成交
+func (client *TradeClient) MatchResults(query *MatchResultsQuery) ([]MatchResult, error) {
+	var results []MatchResult
+	_, err := client.HandleGet("/v1/order/matchresults", &results, query.params())
+	return results, err
+}
+
+// OrderMatchResults 查询某个订单的成交明细
+func (client *TradeClient) OrderMatchResults(orderID int64) ([]MatchResult, error) {
+	var results []MatchResult
+	_, err := client.HandleGet(fmt.Sprintf("/v1/order/orders/%d/matchresults", orderID), &results)
+	return results, err
+}
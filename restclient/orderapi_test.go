@@ -0,0 +1,107 @@
+package restclient
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPlaceOrderParams(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"POST /v1/order/orders/place": `{"status":"ok","data":"59378"}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	id, err := client.PlaceOrder(&PlaceOrderRequest{
+		AccountID:     100009,
+		Symbol:        "btcusdt",
+		Type:          OrderTypeBuyStopLimit,
+		Amount:        "0.001",
+		Price:         "49000.01",
+		StopPrice:     "48000",
+		Operator:      "lte",
+		ClientOrderID: "c1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 59378 {
+		t.Errorf("id = %d, want 59378", id)
+	}
+	_, _, query, body := api.request()
+	if query.Get("Signature") == "" || query.Get("AccessKeyId") != "ak" {
+		t.Errorf("query = %v, want signed request", query)
+	}
+	want := map[string]interface{}{
+		"account-id": "100009", "symbol": "btcusdt", "type": "buy-stop-limit", "amount": "0.001",
+		"price": "49000.01", "stop-price": "48000", "operator": "lte", "client-order-id": "c1",
+	}
+	for key, value := range want {
+		if body[key] != value {
+			t.Errorf("%s = %v, want %v", key, body[key], value)
+		}
+	}
+	if _, isExist := body["source"]; isExist {
+		t.Error("empty source should be omitted")
+	}
+}
+
+func TestOrderQueries(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"GET /v1/order/orders":                          `{"status":"ok","data":[{"id":1,"state":"filled","type":"sell-limit","field-amount":"1.5"}]}`,
+		"GET /v1/order/openOrders":                      `{"status":"ok","data":[{"id":2,"state":"partial-filled","type":"buy-limit","filled-amount":"0.5","filled-fees":"0.001"}]}`,
+		"POST /v1/order/orders/submitCancelClientOrder": `{"status":"ok","data":7}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+
+	orders, err := client.OrderHistory(&OrderHistoryQuery{
+		Symbol: "btcusdt",
+		States: []OrderState{OrderStateFilled, OrderStateCanceled},
+		Types:  []OrderType{OrderTypeSellLimit},
+		Size:   10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, query, _ := api.request()
+	if query.Get("states") != "filled,canceled" || query.Get("types") != "sell-limit" || query.Get("size") != "10" {
+		t.Errorf("query = %v", query)
+	}
+	if query.Get("start-time") != "" {
+		t.Error("zero start-time should be omitted")
+	}
+	if len(orders) != 1 || orders[0].FilledAmount != "1.5" || !orders[0].State.IsFinal() || orders[0].Type.IsBuy() {
+		t.Errorf("orders = %+v", orders)
+	}
+
+	open, err := client.OpenOrders(&OpenOrdersQuery{AccountID: 100009, Symbol: "btcusdt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].FilledAmount != "0.5" || open[0].FilledFees != "0.001" || open[0].State.IsFinal() {
+		t.Errorf("open orders = %+v", open)
+	}
+
+	state, err := client.CancelByClientOrderID("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, body := api.request(); body["client-order-id"] != "c1" || state != 7 {
+		t.Errorf("body = %v, state = %d", body, state)
+	}
+}
+
+func TestOrderUnmarshalPrefersFieldAmount(t *testing.T) {
+	var order Order
+	if err := json.Unmarshal([]byte(`{"id":1,"field-amount":"1","filled-amount":"2"}`), &order); err != nil {
+		t.Fatal(err)
+	}
+	if order.FilledAmount != "1" || order.ID != 1 {
+		t.Errorf("order = %+v", order)
+	}
+	if !OrderTypeSellStopLimitFOK.IsStop() || OrderTypeSellLimitFOK.IsStop() {
+		t.Error("IsStop mismatch")
+	}
+}
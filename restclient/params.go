@@ -0,0 +1,32 @@
+package restclient
+
+import (
+	"strconv"
+	"strings"
+)
+
+// setString 非空时写入参数
+func setString(params map[string]interface{}, key, value string) {
+	if value != "" {
+		params[key] = value
+	}
+}
+
+// setInt 非0时写入参数
+func setInt(params map[string]interface{}, key string, value int64) {
+	if value != 0 {
+		params[key] = value
+	}
+}
+
+// setStrings 非空时以逗号拼接写入参数
+func setStrings(params map[string]interface{}, key string, values []string) {
+	if len(values) > 0 {
+		params[key] = strings.Join(values, ",")
+	}
+}
+
+// parseID 解析接口返回的字符串格式ID
+func parseID(id string) (int64, error) {
+	return strconv.ParseInt(id, 10, 64)
+}
@@ -0,0 +1,230 @@
+package restclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/apierror"
+)
+
+// EndpointCategory 限频分组，火币按分组分别计算请求次数
+type EndpointCategory string
+
+const (
+	CategoryMarket      EndpointCategory = "market"       // 行情及参考数据，按IP限频
+	CategoryOrderPlace  EndpointCategory = "order-place"  // 下单
+	CategoryOrderCancel EndpointCategory = "order-cancel" // 撤单
+	CategoryOrderQuery  EndpointCategory = "order-query"  // 订单查询
+	CategoryAlgoOrder   EndpointCategory = "algo-order"   // 策略委托
+	CategoryAccount     EndpointCategory = "account"      // 账户
+	CategoryWallet      EndpointCategory = "wallet"       // 充提
+	CategoryMargin      EndpointCategory = "margin"       // 杠杆
+	CategorySubUser     EndpointCategory = "sub-user"     // 子用户
+	CategoryDefault     EndpointCategory = "default"      // 其它接口
+)
+
+// LimitMode 达到限频时的处理方式
+type LimitMode int
+
+const (
+	LimitBlock    LimitMode = iota // 阻塞等待至下个窗口
+	LimitFailFast                  // 立即返回限频错误
+)
+
+// RateLimit 一个限频窗口内允许的请求次数
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// DefaultRateLimits 各分组的默认限频，取自火币文档的单UID/单IP限制
+var DefaultRateLimits = map[EndpointCategory]RateLimit{
+	CategoryMarket:      {Requests: 800, Window: time.Second},
+	CategoryOrderPlace:  {Requests: 100, Window: 2 * time.Second},
+	CategoryOrderCancel: {Requests: 100, Window: 2 * time.Second},
+	CategoryOrderQuery:  {Requests: 50, Window: 2 * time.Second},
+	CategoryAlgoOrder:   {Requests: 20, Window: 2 * time.Second},
+	CategoryAccount:     {Requests: 100, Window: 2 * time.Second},
+	CategoryWallet:      {Requests: 20, Window: 2 * time.Second},
+	CategoryMargin:      {Requests: 20, Window: 2 * time.Second},
+	CategorySubUser:     {Requests: 20, Window: 2 * time.Second},
+	CategoryDefault:     {Requests: 10, Window: time.Second},
+}
+
+// 火币在响应头中返回的当前窗口剩余次数及窗口过期时间，
+// 过期时间可能为剩余毫秒数或毫秒时间戳，超过阈值时按时间戳处理
+const (
+	headerRequestsRemain    = "X-HB-RateLimit-Requests-Remain"
+	headerRequestsExpire    = "X-HB-RateLimit-Requests-Expire"
+	absoluteExpireThreshold = int64(1e12)
+)
+
+// categoryRules 按前缀匹配分组，靠前的规则优先
+var categoryRules = []struct {
+	prefix   string
+	category EndpointCategory
+}{
+	{"/market/", CategoryMarket},
+	{"/v1/common/", CategoryMarket},
+	{"/v2/reference/", CategoryMarket},
+	{"/v2/market-status", CategoryMarket},
+	{"/v1/order/orders/place", CategoryOrderPlace},
+	{"/v1/order/batch-orders", CategoryOrderPlace},
+	{"/v1/order/", CategoryOrderQuery},
+	{"/v2/algo-orders", CategoryAlgoOrder},
+	{"/v2/account/deposit", CategoryWallet},
+	{"/v2/account/withdraw", CategoryWallet},
+	{"/v1/dw/withdraw", CategoryWallet},
+	{"/v1/query/deposit-withdraw", CategoryWallet},
+	{"/v1/dw/transfer-", CategoryMargin},
+	{"/v1/margin/", CategoryMargin},
+	{"/v1/cross-margin/", CategoryMargin},
+	{"/v2/account/repayment", CategoryMargin},
+	{"/v2/sub-user/", CategorySubUser},
+	{"/v1/subuser/", CategorySubUser},
+	{"/v1/account/", CategoryAccount},
+	{"/v2/account/", CategoryAccount},
+}
+
+// CategoryOf 返回接口所属的限频分组
+func CategoryOf(path string) EndpointCategory {
+	lower := strings.ToLower(path)
+	if strings.HasPrefix(lower, "/v1/order/") && strings.Contains(lower, "cancel") {
+		return CategoryOrderCancel
+	}
+	for _, rule := range categoryRules {
+		if strings.HasPrefix(lower, rule.prefix) {
+			return rule.category
+		}
+	}
+	return CategoryDefault
+}
+
+// maxPathBuckets 按接口记录的响应头限频状态数量超过该值时清理已过期的记录
+const maxPathBuckets = 256
+
+// RateLimiter 按接口分组限频的客户端限频器，并根据各接口响应头中的剩余次数实时校准
+type RateLimiter struct {
+	mode    LimitMode
+	limits  map[EndpointCategory]RateLimit
+	buckets map[EndpointCategory]*limitBucket
+	paths   map[string]*limitBucket // 响应头返回的各接口剩余次数
+	m       sync.Mutex
+}
+
+type limitBucket struct {
+	remain  int
+	resetAt time.Time
+}
+
+// NewRateLimiter 创建限频器，limits 中未指定的分组使用 DefaultRateLimits
+func NewRateLimiter(mode LimitMode, limits ...map[EndpointCategory]RateLimit) *RateLimiter {
+	merged := make(map[EndpointCategory]RateLimit, len(DefaultRateLimits))
+	for category, limit := range DefaultRateLimits {
+		merged[category] = limit
+	}
+	for _, custom := range limits {
+		for category, limit := range custom {
+			merged[category] = limit
+		}
+	}
+	return &RateLimiter{
+		mode:    mode,
+		limits:  merged,
+		buckets: make(map[EndpointCategory]*limitBucket),
+		paths:   make(map[string]*limitBucket),
+	}
+}
+
+// Wait 占用一次请求额度，额度用尽时按 LimitMode 阻塞或返回错误
+func (limiter *RateLimiter) Wait(ctx context.Context, method, path string) error {
+	category := CategoryOf(path)
+	for {
+		wait, ok := limiter.take(category, path)
+		if ok {
+			return nil
+		}
+		if limiter.mode == LimitFailFast {
+			return fmt.Errorf("%s %s: %w", category, path, apierror.ErrRateLimited)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// take 尝试占用分组及接口的额度，失败时返回距离下个窗口的时间
+func (limiter *RateLimiter) take(category EndpointCategory, path string) (time.Duration, bool) {
+	limiter.m.Lock()
+	defer limiter.m.Unlock()
+	now := time.Now()
+	pathBucket, hasPath := limiter.paths[path]
+	if hasPath && !now.Before(pathBucket.resetAt) {
+		delete(limiter.paths, path)
+		hasPath = false
+	}
+	if hasPath && pathBucket.remain <= 0 {
+		return pathBucket.resetAt.Sub(now), false
+	}
+	bucket := limiter.bucket(category)
+	if !now.Before(bucket.resetAt) {
+		limit := limiter.limits[category]
+		bucket.remain = limit.Requests
+		bucket.resetAt = now.Add(limit.Window)
+	}
+	if bucket.remain > 0 {
+		bucket.remain--
+		if hasPath {
+			pathBucket.remain--
+		}
+		return 0, true
+	}
+	return bucket.resetAt.Sub(now), false
+}
+
+// Update 使用服务端返回的剩余次数及窗口过期时间校准该接口的状态，不影响同一分组的其它接口
+func (limiter *RateLimiter) Update(method, path string, header http.Header) {
+	remain, err := strconv.Atoi(header.Get(headerRequestsRemain))
+	if err != nil {
+		return
+	}
+	expire, err := strconv.ParseInt(header.Get(headerRequestsExpire), 10, 64)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	bucket := &limitBucket{remain: remain}
+	if expire > absoluteExpireThreshold {
+		bucket.resetAt = time.Unix(0, expire*int64(time.Millisecond))
+	} else {
+		bucket.resetAt = now.Add(time.Duration(expire) * time.Millisecond)
+	}
+	limiter.m.Lock()
+	defer limiter.m.Unlock()
+	if len(limiter.paths) >= maxPathBuckets {
+		for key, b := range limiter.paths {
+			if !now.Before(b.resetAt) {
+				delete(limiter.paths, key)
+			}
+		}
+	}
+	limiter.paths[path] = bucket
+}
+
+func (limiter *RateLimiter) bucket(category EndpointCategory) *limitBucket {
+	bucket, isExist := limiter.buckets[category]
+	if !isExist {
+		bucket = &limitBucket{}
+		limiter.buckets[category] = bucket
+	}
+	return bucket
+}
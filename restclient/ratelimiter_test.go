@@ -0,0 +1,126 @@
+package restclient
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/apierror"
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+func TestCategoryOf(t *testing.T) {
+	tests := map[string]EndpointCategory{
+		"/market/history/kline":                    CategoryMarket,
+		"/v1/common/symbols":                       CategoryMarket,
+		"/v1/order/orders/place":                   CategoryOrderPlace,
+		"/v1/order/batch-orders":                   CategoryOrderPlace,
+		"/v1/order/orders/123/submitcancel":        CategoryOrderCancel,
+		"/v1/order/orders/submitCancelClientOrder": CategoryOrderCancel,
+		"/v1/order/orders/123":                     CategoryOrderQuery,
+		"/v2/algo-orders/opening":                  CategoryAlgoOrder,
+		"/v1/account/accounts":                     CategoryAccount,
+		"/v2/account/withdraw/quota":               CategoryWallet,
+		"/v2/account/repayment":                    CategoryMargin,
+		"/v2/sub-user/user-list":                   CategorySubUser,
+		"/v1/unknown":                              CategoryDefault,
+	}
+	for path, want := range tests {
+		if got := CategoryOf(path); got != want {
+			t.Errorf("CategoryOf(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
+
+func TestRateLimiterFailFast(t *testing.T) {
+	limiter := NewRateLimiter(LimitFailFast, map[EndpointCategory]RateLimit{
+		CategoryOrderQuery: {Requests: 2, Window: time.Hour},
+	})
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx, http.MethodGet, "/v1/order/orders/1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := limiter.Wait(ctx, http.MethodGet, "/v1/order/orders/1"); !apierror.IsRateLimited(err) {
+		t.Fatalf("Wait = %v, want rate limited", err)
+	}
+	if err := limiter.Wait(ctx, http.MethodGet, "/v1/account/accounts"); err != nil {
+		t.Fatalf("other category: %v", err)
+	}
+}
+
+func TestRateLimiterBlock(t *testing.T) {
+	limiter := NewRateLimiter(LimitBlock, map[EndpointCategory]RateLimit{
+		CategoryDefault: {Requests: 1, Window: 50 * time.Millisecond},
+	})
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx, http.MethodGet, "/x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("second Wait returned after %v, want blocking until next window", elapsed)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := limiter.Wait(ctx, http.MethodGet, "/x"); err != context.Canceled {
+		t.Errorf("Wait with canceled ctx = %v", err)
+	}
+}
+
+func TestRateLimiterUpdatePerPath(t *testing.T) {
+	limiter := NewRateLimiter(LimitFailFast)
+	header := http.Header{}
+	header.Set(headerRequestsRemain, "0")
+	header.Set(headerRequestsExpire, "60000")
+	limiter.Update(http.MethodGet, "/v1/order/openOrders", header)
+
+	ctx := context.Background()
+	if err := limiter.Wait(ctx, http.MethodGet, "/v1/order/openOrders"); !apierror.IsRateLimited(err) {
+		t.Errorf("exhausted path: Wait = %v, want rate limited", err)
+	}
+	if err := limiter.Wait(ctx, http.MethodGet, "/v1/order/matchresults"); err != nil {
+		t.Errorf("same category, other path: Wait = %v", err)
+	}
+
+	header.Set(headerRequestsRemain, "1")
+	header.Set(headerRequestsExpire, "1")
+	limiter.Update(http.MethodGet, "/v1/order/openOrders", header)
+	time.Sleep(5 * time.Millisecond)
+	if err := limiter.Wait(ctx, http.MethodGet, "/v1/order/openOrders"); err != nil {
+		t.Errorf("expired path state: Wait = %v", err)
+	}
+}
+
+func TestRateLimiterFailFastWithRetry(t *testing.T) {
+	var calls int32
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeJSON(w, http.StatusOK, `{"status":"ok","data":[]}`)
+	})
+	defer ts.Close()
+	limiter := NewRateLimiter(LimitFailFast, map[EndpointCategory]RateLimit{
+		CategoryMarket: {Requests: 1, Window: time.Hour},
+	})
+	policy := config.RetryPolicy{MaxAttempts: 3, BaseDelay: 50 * time.Millisecond}
+	client, _ := NewMarketClient(append(testOptions(ts), config.WithRateLimiter(limiter), config.WithRetry(policy))...)
+	if _, err := client.Get("/market/tickers"); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err := client.Get("/market/tickers")
+	if !apierror.IsRateLimited(err) {
+		t.Fatalf("Get = %v, want rate limited", err)
+	}
+	if elapsed := time.Since(start); elapsed >= policy.BaseDelay {
+		t.Errorf("fail fast took %v, want no retry", elapsed)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
@@ -0,0 +1,117 @@
+package restclient
+
+// Symbol 交易对信息
+type Symbol struct {
+	BaseCurrency             string  `json:"base-currency"`
+	QuoteCurrency            string  `json:"quote-currency"`
+	PricePrecision           int     `json:"price-precision"`
+	AmountPrecision          int     `json:"amount-precision"`
+	ValuePrecision           int     `json:"value-precision"`
+	SymbolPartition          string  `json:"symbol-partition"`
+	Symbol                   string  `json:"symbol"`
+	State                    string  `json:"state"`
+	MinOrderAmt              float64 `json:"min-order-amt"`
+	MaxOrderAmt              float64 `json:"max-order-amt"`
+	MinOrderValue            float64 `json:"min-order-value"`
+	LimitOrderMinOrderAmt    float64 `json:"limit-order-min-order-amt"`
+	LimitOrderMaxOrderAmt    float64 `json:"limit-order-max-order-amt"`
+	SellMarketMinOrderAmt    float64 `json:"sell-market-min-order-amt"`
+	SellMarketMaxOrderAmt    float64 `json:"sell-market-max-order-amt"`
+	BuyMarketMaxOrderValue   float64 `json:"buy-market-max-order-value"`
+	LeverageRatio            float64 `json:"leverage-ratio"`
+	SuperMarginLeverageRatio float64 `json:"super-margin-leverage-ratio"`
+	APITrading               string  `json:"api-trading"`
+}
+
+// IsOnline 交易对是否处于可交易状态
+func (s *Symbol) IsOnline() bool {
+	return s.State == "online"
+}
+
+// CurrencyChain 币种所在链的充提信息
+type CurrencyChain struct {
+	Chain                   string `json:"chain"`
+	DisplayName             string `json:"displayName"`
+	BaseChain               string `json:"baseChain"`
+	BaseChainProtocol       string `json:"baseChainProtocol"`
+	IsDynamic               bool   `json:"isDynamic"`
+	NumOfConfirmations      int    `json:"numOfConfirmations"`
+	NumOfFastConfirmations  int    `json:"numOfFastConfirmations"`
+	DepositStatus           string `json:"depositStatus"`
+	MinDepositAmt           string `json:"minDepositAmt"`
+	WithdrawStatus          string `json:"withdrawStatus"`
+	MinWithdrawAmt          string `json:"minWithdrawAmt"`
+	MaxWithdrawAmt          string `json:"maxWithdrawAmt"`
+	WithdrawPrecision       int    `json:"withdrawPrecision"`
+	WithdrawQuotaPerDay     string `json:"withdrawQuotaPerDay"`
+	WithdrawQuotaPerYear    string `json:"withdrawQuotaPerYear"`
+	WithdrawQuotaTotal      string `json:"withdrawQuotaTotal"`
+	WithdrawFeeType         string `json:"withdrawFeeType"`
+	TransactFeeWithdraw     string `json:"transactFeeWithdraw"`
+	MinTransactFeeWithdraw  string `json:"minTransactFeeWithdraw"`
+	MaxTransactFeeWithdraw  string `json:"maxTransactFeeWithdraw"`
+	TransactFeeRateWithdraw string `json:"transactFeeRateWithdraw"`
+}
+
+// CurrencyReference 币链参考信息
+type CurrencyReference struct {
+	Currency   string          `json:"currency"`
+	AssetType  int             `json:"assetType"`
+	InstStatus string          `json:"instStatus"`
+	Chains     []CurrencyChain `json:"chains"`
+}
+
+// MarketStatus 市场状态，1 正常 2 暂停 3 撤单
+type MarketStatus struct {
+	MarketStatus    int    `json:"marketStatus"`
+	HaltStartTime   int64  `json:"haltStartTime"`
+	HaltEndTime     int64  `json:"haltEndTime"`
+	HaltReason      int    `json:"haltReason"`
+	AffectedSymbols string `json:"affectedSymbols"`
+}
+
+// IsNormal 市场是否正常
+func (s *MarketStatus) IsNormal() bool {
+	return s.MarketStatus == 1
+}
+
+// Symbols 获取所有交易对
+func (client *MarketClient) Symbols() ([]Symbol, error) {
+	var symbols []Symbol
+	_, err := client.HandleGet("/v1/common/symbols", &symbols)
+	return symbols, err
+}
+
+// Currencys 获取所有币种
+func (client *MarketClient) Currencys() ([]string, error) {
+	var currencys []string
+	_, err := client.HandleGet("/v1/common/currencys", &currencys)
+	return currencys, err
+}
+
+// CurrencyReferences 获取币链参考信息，currency 为空时返回所有币种
+func (client *MarketClient) CurrencyReferences(currency string) ([]CurrencyReference, error) {
+	params := map[string]interface{}{}
+	if currency != "" {
+		params["currency"] = currency
+	}
+	var references []CurrencyReference
+	_, err := client.HandleGet("/v2/reference/currencies", &references, params)
+	return references, err
+}
+
+// MarketStatus 获取当前市场状态
+func (client *MarketClient) MarketStatus() (*MarketStatus, error) {
+	var status MarketStatus
+	if _, err := client.HandleGet("/v2/market-status", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Timestamp 获取服务器毫秒时间戳
+func (client *MarketClient) Timestamp() (int64, error) {
+	var timestamp int64
+	_, err := client.HandleGet("/v1/common/timestamp", &timestamp)
+	return timestamp, err
+}
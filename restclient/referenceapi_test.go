@@ -0,0 +1,46 @@
+package restclient
+
+import (
+	"testing"
+)
+
+func TestReferenceAPI(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"GET /v1/common/symbols":       `{"status":"ok","data":[{"base-currency":"btc","quote-currency":"usdt","symbol":"btcusdt","state":"online","price-precision":2,"min-order-value":5},{"symbol":"xyzusdt","state":"offline"}]}`,
+		"GET /v2/reference/currencies": `{"code":200,"data":[{"currency":"usdt","instStatus":"normal","chains":[{"chain":"trc20usdt","depositStatus":"allowed","numOfConfirmations":1}]}]}`,
+		"GET /v2/market-status":        `{"code":200,"message":"success","data":{"marketStatus":2,"haltStartTime":1,"haltEndTime":2,"haltReason":2,"affectedSymbols":"btcusdt"}}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewMarketClient(testOptions(ts)...)
+
+	symbols, err := client.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 2 || symbols[0].PricePrecision != 2 || symbols[0].MinOrderValue != 5 {
+		t.Fatalf("symbols = %+v", symbols)
+	}
+	if !symbols[0].IsOnline() || symbols[1].IsOnline() {
+		t.Error("IsOnline mismatch")
+	}
+
+	references, err := client.CurrencyReferences("usdt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, query, _ := api.request(); query.Get("currency") != "usdt" {
+		t.Errorf("query = %v", query)
+	}
+	if len(references) != 1 || len(references[0].Chains) != 1 || references[0].Chains[0].NumOfConfirmations != 1 {
+		t.Errorf("references = %+v", references)
+	}
+
+	status, err := client.MarketStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.IsNormal() || status.AffectedSymbols != "btcusdt" {
+		t.Errorf("status = %+v", status)
+	}
+}
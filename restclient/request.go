@@ -2,48 +2,107 @@ package restclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 
+	"github.com/feeeei/huobiapi-go/apierror"
+	"github.com/feeeei/huobiapi-go/config"
 	"github.com/feeeei/huobiapi-go/utils"
 
 	"github.com/bitly/go-simplejson"
 )
 
-func request(method, url string, params map[string]interface{}) (*simplejson.Json, error) {
+// request 发送请求，配置了重试策略时对可安全重试的错误自动重试
+func request(ctx context.Context, cfg *config.Config, method, url string, params interface{}) (*simplejson.Json, error) {
+	for attempt := 1; ; attempt++ {
+		json, err := doRequest(ctx, cfg, method, url, params)
+		if err == nil || !cfg.Retry.CanRetry(attempt) || !isRetryable(method, err) {
+			return json, err
+		}
+		cfg.Logger.Println("Retry request", method, url, "error:", err)
+		if err := sleepBackoff(ctx, cfg.Retry, attempt); err != nil {
+			return json, err
+		}
+	}
+}
+
+func doRequest(ctx context.Context, cfg *config.Config, method, url string, params interface{}) (*simplejson.Json, error) {
 	url, body := parameters(method, url, params)
 	var req *http.Request
 	var err error
 	if body != nil {
-		req, err = http.NewRequest(method, url, body)
+		req, err = http.NewRequestWithContext(ctx, method, url, body)
 	} else {
-		req, err = http.NewRequest(method, url, nil)
+		req, err = http.NewRequestWithContext(ctx, method, url, nil)
 	}
 	if err != nil {
 		return nil, err
 	}
-	addHeaders(method, req)
-	resp, err := http.DefaultClient.Do(req)
+	addHeaders(cfg, method, req)
+	if cfg.RateLimiter != nil {
+		if err := cfg.RateLimiter.Wait(ctx, method, req.URL.Path); err != nil {
+			return nil, &limiterError{err}
+		}
+	}
+	resp, err := cfg.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if cfg.RateLimiter != nil {
+		cfg.RateLimiter.Update(method, req.URL.Path, resp.Header)
+	}
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
-	json, err := simplejson.NewJson(respBody)
+	return checkResponse(req.URL.Path, resp.StatusCode, respBody)
+}
+
+// checkResponse 解析响应，将v1接口的 status=error、v2接口的 code!=200 及HTTP错误转换为 APIError
+func checkResponse(path string, httpStatus int, body []byte) (*simplejson.Json, error) {
+	json, err := simplejson.NewJson(body)
+	if err != nil {
+		if httpStatus >= http.StatusBadRequest {
+			return nil, &apierror.APIError{Source: apierror.SourceREST, HTTPStatus: httpStatus, Endpoint: path, Body: body}
+		}
+		return nil, err
+	}
 	var status = json.Get("status").MustString()
 	if status == "error" {
-		return json, fmt.Errorf(json.Get("err-msg").MustString())
+		return json, &apierror.APIError{
+			Source:     apierror.SourceREST,
+			ErrCode:    json.Get("err-code").MustString(),
+			Message:    json.Get("err-msg").MustString(),
+			HTTPStatus: httpStatus,
+			Endpoint:   path,
+			Body:       body,
+		}
+	}
+	// v2 接口使用 code 表示状态，200 为成功
+	if code, isExist := json.CheckGet("code"); isExist && status == "" {
+		if c, err := code.Int(); err == nil && c != 200 {
+			return json, &apierror.APIError{
+				Source:     apierror.SourceREST,
+				Code:       c,
+				Message:    json.Get("message").MustString(),
+				HTTPStatus: httpStatus,
+				Endpoint:   path,
+				Body:       body,
+			}
+		}
+	}
+	if httpStatus >= http.StatusBadRequest {
+		return json, &apierror.APIError{Source: apierror.SourceREST, HTTPStatus: httpStatus, Endpoint: path, Body: body}
 	}
 	return json, nil
 }
 
-func addHeaders(method string, req *http.Request) *http.Request {
-	req.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/39.0.2171.71 Safari/537.36")
+func addHeaders(cfg *config.Config, method string, req *http.Request) *http.Request {
+	req.Header.Add("User-Agent", cfg.UserAgent)
 	if isGetMethod(method) {
 		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 	} else {
@@ -52,12 +111,13 @@ func addHeaders(method string, req *http.Request) *http.Request {
 	return req
 }
 
-func parameters(method, urlStr string, params map[string]interface{}) (string, *bytes.Buffer) {
+func parameters(method, urlStr string, params interface{}) (string, *bytes.Buffer) {
 	if !isGetMethod(method) {
 		b, _ := json.Marshal(params)
 		return urlStr, bytes.NewBuffer(b)
 	}
-	return urlStr + "?" + utils.EncodeQueryString(params), nil
+	query, _ := params.(map[string]interface{})
+	return urlStr + "?" + utils.EncodeQueryString(query), nil
 }
 
 func isValidParams(params ...interface{}) error {
@@ -0,0 +1,73 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/apierror"
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+// limiterError 客户端限频器拒绝发送请求，不做重试，否则 LimitFailFast 会变为按重试策略等待
+type limiterError struct {
+	err error
+}
+
+func (e *limiterError) Error() string { return e.err.Error() }
+func (e *limiterError) Unwrap() error { return e.err }
+
+// isRetryable 判断请求失败后能否安全重试。
+// 服务端返回的限频错误说明未处理该请求，任何方法都可以重试；
+// 网络错误及5xx无法确认服务端是否已处理，只对GET请求重试
+func isRetryable(method string, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var limiterErr *limiterError
+	if errors.As(err, &limiterErr) {
+		return false
+	}
+	if apierror.IsRateLimited(err) {
+		return true
+	}
+	return isGetMethod(method) && isAmbiguous(err)
+}
+
+// isAmbiguous 判断错误发生时服务端是否可能已处理了请求，ctx 结束时调用方已放弃该请求，不做判断
+func isAmbiguous(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if apiErr, ok := apierror.AsAPIError(err); ok {
+		return apiErr.HTTPStatus >= http.StatusInternalServerError
+	}
+	if apierror.IsRateLimited(err) {
+		return false
+	}
+	return true
+}
+
+// duplicateClientOrderIDCodes client-order-id 重复时的错误码
+var duplicateClientOrderIDCodes = map[string]bool{
+	"client-order-id-duplicated": true,
+}
+
+// isDuplicateClientOrderID 是否为 client-order-id 重复导致的下单失败
+func isDuplicateClientOrderID(err error) bool {
+	apiErr, ok := apierror.AsAPIError(err)
+	return ok && duplicateClientOrderIDCodes[apiErr.ErrCode]
+}
+
+// sleepBackoff 按重试策略等待，ctx 结束时提前返回
+func sleepBackoff(ctx context.Context, policy *config.RetryPolicy, attempt int) error {
+	timer := time.NewTimer(policy.Backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,175 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/apierror"
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+var testRetryPolicy = config.RetryPolicy{MaxAttempts: 3, BaseDelay: 20 * time.Millisecond}
+
+func TestIsAmbiguous(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"network", errors.New("connection reset"), true},
+		{"5xx", &apierror.APIError{HTTPStatus: http.StatusBadGateway}, true},
+		{"4xx", &apierror.APIError{HTTPStatus: http.StatusBadRequest}, false},
+		{"rate limited", &apierror.APIError{ErrCode: "api-request-too-frequent"}, false},
+		{"canceled", context.Canceled, false},
+		{"deadline", context.DeadlineExceeded, false},
+	}
+	for _, test := range tests {
+		if got := isAmbiguous(test.err); got != test.want {
+			t.Errorf("%s: isAmbiguous = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	gatewayErr := &apierror.APIError{HTTPStatus: http.StatusBadGateway}
+	if !isRetryable(http.MethodGet, gatewayErr) {
+		t.Error("GET 5xx should be retryable")
+	}
+	if isRetryable(http.MethodPost, gatewayErr) {
+		t.Error("POST 5xx should not be retryable")
+	}
+	if !isRetryable(http.MethodPost, &apierror.APIError{HTTPStatus: http.StatusTooManyRequests}) {
+		t.Error("POST 429 should be retryable")
+	}
+	if isRetryable(http.MethodGet, context.Canceled) {
+		t.Error("canceled request should not be retryable")
+	}
+}
+
+func TestRequestRetry(t *testing.T) {
+	var m sync.Mutex
+	calls := 0
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		m.Lock()
+		calls++
+		n := calls
+		m.Unlock()
+		if n < 3 {
+			writeJSON(w, http.StatusBadGateway, `{}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{"status":"ok","data":1}`)
+	})
+	defer ts.Close()
+	client, _ := NewMarketClient(append(testOptions(ts), config.WithRetry(testRetryPolicy))...)
+	if _, err := client.Get("/v1/common/timestamp"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+// placeOrderServer 模拟下单接口，place 依次返回 placeResults，getClientOrder 依次返回 lookupResults
+type placeOrderServer struct {
+	m             sync.Mutex
+	placeResults  []func(w http.ResponseWriter)
+	lookupResults []func(w http.ResponseWriter)
+	places        int
+	lookups       []time.Time
+	start         time.Time
+}
+
+func (server *placeOrderServer) handler(w http.ResponseWriter, r *http.Request) {
+	server.m.Lock()
+	defer server.m.Unlock()
+	switch r.URL.Path {
+	case "/v1/order/orders/place":
+		server.placeResults[server.places](w)
+		server.places++
+	case "/v1/order/orders/getClientOrder":
+		server.lookupResults[len(server.lookups)](w)
+		server.lookups = append(server.lookups, time.Now())
+	default:
+		writeJSON(w, http.StatusNotFound, `{}`)
+	}
+}
+
+func placed(w http.ResponseWriter)     { writeJSON(w, http.StatusOK, `{"status":"ok","data":"42"}`) }
+func badGateway(w http.ResponseWriter) { writeJSON(w, http.StatusBadGateway, `{}`) }
+func duplicated(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, `{"status":"error","err-code":"client-order-id-duplicated","err-msg":"duplicated"}`)
+}
+func orderFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, `{"status":"ok","data":{"id":42,"client-order-id":"c1"}}`)
+}
+func orderNotFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, `{"status":"error","err-code":"base-record-invalid","err-msg":"record invalid"}`)
+}
+
+func (server *placeOrderServer) placeOrder(t *testing.T) (int64, error) {
+	t.Helper()
+	ts := newTestServer(server.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", append(testOptions(ts), config.WithRetry(testRetryPolicy))...)
+	server.start = time.Now()
+	return client.PlaceOrder(&PlaceOrderRequest{AccountID: 1, Symbol: "btcusdt", Type: OrderTypeBuyLimit, Amount: "1", Price: "1", ClientOrderID: "c1"})
+}
+
+func TestPlaceOrderFoundAfterAmbiguousFailure(t *testing.T) {
+	server := &placeOrderServer{
+		placeResults:  []func(http.ResponseWriter){badGateway},
+		lookupResults: []func(http.ResponseWriter){orderFound},
+	}
+	id, err := server.placeOrder(t)
+	if err != nil || id != 42 {
+		t.Fatalf("PlaceOrder = %d, %v, want 42", id, err)
+	}
+	if server.places != 1 {
+		t.Errorf("places = %d, want 1", server.places)
+	}
+	if wait := server.lookups[0].Sub(server.start); wait < testRetryPolicy.BaseDelay/2 {
+		t.Errorf("lookup after %v, want backoff before lookup", wait)
+	}
+}
+
+func TestPlaceOrderResendDuplicated(t *testing.T) {
+	server := &placeOrderServer{
+		placeResults:  []func(http.ResponseWriter){badGateway, duplicated},
+		lookupResults: []func(http.ResponseWriter){orderNotFound, orderFound},
+	}
+	id, err := server.placeOrder(t)
+	if err != nil || id != 42 {
+		t.Fatalf("PlaceOrder = %d, %v, want 42", id, err)
+	}
+	if server.places != 2 {
+		t.Errorf("places = %d, want 2", server.places)
+	}
+}
+
+func TestPlaceOrderResend(t *testing.T) {
+	server := &placeOrderServer{
+		placeResults:  []func(http.ResponseWriter){badGateway, placed},
+		lookupResults: []func(http.ResponseWriter){orderNotFound},
+	}
+	id, err := server.placeOrder(t)
+	if err != nil || id != 42 {
+		t.Fatalf("PlaceOrder = %d, %v, want 42", id, err)
+	}
+}
+
+func TestPlaceOrderNotAmbiguous(t *testing.T) {
+	server := &placeOrderServer{
+		placeResults: []func(http.ResponseWriter){duplicated},
+	}
+	if _, err := server.placeOrder(t); err == nil {
+		t.Fatal("want error")
+	}
+	if len(server.lookups) != 0 {
+		t.Errorf("lookups = %d, want 0", len(server.lookups))
+	}
+}
@@ -0,0 +1,66 @@
+package restclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+// newTestServer 启动本地 HTTPS 服务，调用方负责 Close
+func newTestServer(handler http.HandlerFunc) *httptest.Server {
+	return httptest.NewTLSServer(handler)
+}
+
+// testOptions 指向本地服务的配置项
+func testOptions(server *httptest.Server) []config.Option {
+	return []config.Option{
+		config.WithHost(strings.TrimPrefix(server.URL, "https://")),
+		config.WithHTTPClient(server.Client()),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+// fakeAPI 按 "METHOD path" 返回固定响应，并记录最近一次请求的参数
+type fakeAPI struct {
+	routes map[string]string
+	m      sync.Mutex
+	query  url.Values
+	body   map[string]interface{}
+	method string
+	path   string
+}
+
+func newFakeAPI(routes map[string]string) *fakeAPI {
+	return &fakeAPI{routes: routes}
+}
+
+func (api *fakeAPI) handler(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	api.m.Lock()
+	api.method, api.path, api.query, api.body = r.Method, r.URL.Path, r.URL.Query(), body
+	api.m.Unlock()
+	response, isExist := api.routes[r.Method+" "+r.URL.Path]
+	if !isExist {
+		writeJSON(w, http.StatusNotFound, `{"status":"error","err-code":"not-found","err-msg":"not found"}`)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// request 最近一次请求
+func (api *fakeAPI) request() (method, path string, query url.Values, body map[string]interface{}) {
+	api.m.Lock()
+	defer api.m.Unlock()
+	return api.method, api.path, api.query, api.body
+}
@@ -0,0 +1,155 @@
+package restclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SubUserTransferType 母子用户划转类型
+type SubUserTransferType string
+
+const (
+	TransferMasterIn             SubUserTransferType = "master-transfer-in"               // 子用户划转给母用户
+	TransferMasterOut            SubUserTransferType = "master-transfer-out"              // 母用户划转给子用户
+	TransferMasterPointIn        SubUserTransferType = "master-point-transfer-in"         // 子用户划转点卡给母用户
+	TransferMasterPointOut       SubUserTransferType = "master-point-transfer-out"        // 母用户划转点卡给子用户
+	TransferMasterIsolatedOut    SubUserTransferType = "master-transfer-out-isolated"     // 母用户划转给子用户逐仓杠杆账户
+	TransferMasterCrossMarginOut SubUserTransferType = "master-transfer-out-cross-margin" // 母用户划转给子用户全仓杠杆账户
+)
+
+// SubUserCreation 创建子用户的参数
+type SubUserCreation struct {
+	UserName string `json:"userName"`
+	Note     string `json:"note,omitempty"`
+}
+
+// SubUserCreationResult 创建子用户的结果
+type SubUserCreationResult struct {
+	UserName   string `json:"userName"`
+	Note       string `json:"note"`
+	UID        int64  `json:"uid"`
+	ErrCode    string `json:"errCode"`
+	ErrMessage string `json:"errMessage"`
+}
+
+// SubUserAPIKeyRequest 子用户 API key 创建/修改参数
+type SubUserAPIKeyRequest struct {
+	OTPToken    string   // 母用户谷歌验证码，仅创建时需要
+	SubUID      int64    // 子用户UID
+	AccessKey   string   // 仅修改时需要
+	Note        string   // API key 备注
+	Permission  []string // readOnly、trade
+	IPAddresses []string // 绑定的IP地址，最多20个
+}
+
+func (req *SubUserAPIKeyRequest) params() map[string]interface{} {
+	params := map[string]interface{}{"subUid": req.SubUID}
+	setString(params, "otpToken", req.OTPToken)
+	setString(params, "accessKey", req.AccessKey)
+	setString(params, "note", req.Note)
+	setStrings(params, "permission", req.Permission)
+	setStrings(params, "ipAddresses", req.IPAddresses)
+	return params
+}
+
+// SubUserAPIKey 子用户 API key，SecretKey 仅在创建时返回
+type SubUserAPIKey struct {
+	Note        string `json:"note"`
+	AccessKey   string `json:"accessKey"`
+	SecretKey   string `json:"secretKey"`
+	Permission  string `json:"permission"`
+	IPAddresses string `json:"ipAddresses"`
+}
+
+// SubUserTransferability 子用户可划转设置结果
+type SubUserTransferability struct {
+	SubUID        int64  `json:"subUid"`
+	AccountType   string `json:"accountType"`
+	Transferrable bool   `json:"transferrable"`
+	ErrCode       string `json:"errCode"`
+	ErrMessage    string `json:"errMessage"`
+}
+
+// SubUserTransferRequest 母子用户划转参数
+type SubUserTransferRequest struct {
+	SubUID   int64
+	Currency string
+	Amount   string
+	Type     SubUserTransferType
+}
+
+// CreateSubUsers 批量创建子用户，每个用户单独返回结果
+func (client *TradeClient) CreateSubUsers(users []SubUserCreation) ([]SubUserCreationResult, error) {
+	var results []SubUserCreationResult
+	_, err := client.HandlePost("/v2/sub-user/creation", &results,
+		map[string]interface{}{"userList": users})
+	return results, err
+}
+
+// CreateSubUserAPIKey 为子用户创建 API key
+func (client *TradeClient) CreateSubUserAPIKey(req *SubUserAPIKeyRequest) (*SubUserAPIKey, error) {
+	var key SubUserAPIKey
+	if _, err := client.HandlePost("/v2/sub-user/api-key-generation", &key, req.params()); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ModifySubUserAPIKey 修改子用户 API key 的备注、权限及绑定IP
+func (client *TradeClient) ModifySubUserAPIKey(req *SubUserAPIKeyRequest) (*SubUserAPIKey, error) {
+	var key SubUserAPIKey
+	if _, err := client.HandlePost("/v2/sub-user/api-key-modification", &key, req.params()); err != nil {
+		return nil, err
+	}
+	key.AccessKey = req.AccessKey
+	return &key, nil
+}
+
+// DeleteSubUserAPIKey 删除子用户 API key
+func (client *TradeClient) DeleteSubUserAPIKey(subUID int64, accessKey string) error {
+	_, err := client.Post("/v2/sub-user/api-key-deletion",
+		map[string]interface{}{"subUid": subUID, "accessKey": accessKey})
+	return err
+}
+
+// SetSubUserTransferability 设置子用户指定账户类型是否可划转，accountType 默认为 spot
+func (client *TradeClient) SetSubUserTransferability(subUIDs []int64, accountType string, transferrable bool) ([]SubUserTransferability, error) {
+	uids := make([]string, len(subUIDs))
+	for i, uid := range subUIDs {
+		uids[i] = fmt.Sprint(uid)
+	}
+	params := map[string]interface{}{
+		"subUids":       strings.Join(uids, ","),
+		"transferrable": transferrable,
+	}
+	setString(params, "accountType", accountType)
+	var results []SubUserTransferability
+	_, err := client.HandlePost("/v2/sub-user/transferability", &results, params)
+	return results, err
+}
+
+// SubUserBalances 查询子用户各账户余额
+func (client *TradeClient) SubUserBalances(subUID int64) ([]Balance, error) {
+	var balances []Balance
+	_, err := client.HandleGet(fmt.Sprintf("/v1/account/accounts/%d", subUID), &balances)
+	return balances, err
+}
+
+// SubUserAggregateBalance 查询所有子用户的币种汇总余额
+func (client *TradeClient) SubUserAggregateBalance() ([]BalanceItem, error) {
+	var balances []BalanceItem
+	_, err := client.HandleGet("/v1/subuser/aggregate-balance", &balances)
+	return balances, err
+}
+
+// SubUserTransfer 母子用户之间划转资产，返回划转ID
+func (client *TradeClient) SubUserTransfer(req *SubUserTransferRequest) (int64, error) {
+	var id int64
+	_, err := client.HandlePost("/v1/subuser/transfer", &id, map[string]interface{}{
+		"sub-uid":  req.SubUID,
+		"currency": req.Currency,
+		"amount":   req.Amount,
+		"type":     string(req.Type),
+	})
+	return id, err
+}
@@ -0,0 +1,62 @@
+package restclient
+
+import (
+	"testing"
+)
+
+func TestSubUserAPI(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"POST /v2/sub-user/creation":           `{"code":200,"data":[{"userName":"alice","uid":123},{"userName":"bob","errCode":"2002","errMessage":"invalid field value in userName"}]}`,
+		"POST /v2/sub-user/api-key-generation": `{"code":200,"data":{"note":"bot","accessKey":"k1","secretKey":"s1","permission":"readOnly,trade","ipAddresses":"1.1.1.1"}}`,
+		"POST /v2/sub-user/transferability":    `{"code":200,"data":[{"subUid":123,"accountType":"spot","transferrable":true}]}`,
+		"POST /v1/subuser/transfer":            `{"status":"ok","data":12345}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+
+	users, err := client.CreateSubUsers([]SubUserCreation{{UserName: "alice", Note: "n"}, {UserName: "bob"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].UID != 123 || users[1].ErrCode != "2002" {
+		t.Errorf("users = %+v", users)
+	}
+	_, _, _, body := api.request()
+	if list, _ := body["userList"].([]interface{}); len(list) != 2 {
+		t.Errorf("body = %v", body)
+	}
+
+	key, err := client.CreateSubUserAPIKey(&SubUserAPIKeyRequest{OTPToken: "123456", SubUID: 123, Note: "bot", Permission: []string{"readOnly", "trade"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.SecretKey != "s1" {
+		t.Errorf("key = %+v", key)
+	}
+	if _, _, _, body := api.request(); body["permission"] != "readOnly,trade" || body["otpToken"] != "123456" || body["subUid"] != float64(123) {
+		t.Errorf("body = %v", body)
+	}
+
+	results, err := client.SetSubUserTransferability([]int64{123, 456}, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, body := api.request(); body["subUids"] != "123,456" || body["transferrable"] != true {
+		t.Errorf("body = %v", body)
+	}
+	if _, _, _, body := api.request(); body["accountType"] != nil {
+		t.Error("empty accountType should be omitted")
+	}
+	if len(results) != 1 || !results[0].Transferrable {
+		t.Errorf("results = %+v", results)
+	}
+
+	id, err := client.SubUserTransfer(&SubUserTransferRequest{SubUID: 123, Currency: "usdt", Amount: "10", Type: TransferMasterOut})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, body := api.request(); body["type"] != "master-transfer-out" || body["sub-uid"] != float64(123) || id != 12345 {
+		t.Errorf("body = %v, id = %d", body, id)
+	}
+}
@@ -1,6 +1,7 @@
 package restclient
 
 import (
+	"context"
 	"net/url"
 
 	"github.com/bitly/go-simplejson"
@@ -11,19 +12,45 @@ import (
 
 type TradeClient struct {
 	Endpoint *url.URL
+	cfg      *config.Config
 	sign     *sign.Sign
+	ctx      context.Context
 }
 
 // NewTradeClient REST格式交易Client
-func NewTradeClient(accessKeyID, accessKeySecret string) (*TradeClient, error) {
+func NewTradeClient(accessKeyID, accessKeySecret string, options ...config.Option) (*TradeClient, error) {
+	cfg := config.New(options...)
+	if err := cfg.HTTPProxyErr(); err != nil {
+		return nil, err
+	}
 	return &TradeClient{
-		Endpoint: config.HuobiRestEndpoint,
-		sign:     sign.NewSign(accessKeyID, accessKeySecret, "2"),
+		Endpoint: cfg.RestEndpoint,
+		cfg:      cfg,
+		sign:     cfg.NewSign(accessKeyID, accessKeySecret, "2"),
 	}, nil
 }
 
+// WithContext 返回绑定ctx的Client副本，副本上的所有请求（包括类型化接口）都受ctx控制
+func (client *TradeClient) WithContext(ctx context.Context) *TradeClient {
+	c := *client
+	c.ctx = ctx
+	return &c
+}
+
+func (client *TradeClient) context() context.Context {
+	if client.ctx == nil {
+		return context.Background()
+	}
+	return client.ctx
+}
+
 // Get Get同步请求
 func (client *TradeClient) Get(path string, params ...map[string]interface{}) (*simplejson.Json, error) {
+	return client.GetContext(client.context(), path, params...)
+}
+
+// GetContext 可取消的Get同步请求
+func (client *TradeClient) GetContext(ctx context.Context, path string, params ...map[string]interface{}) (*simplejson.Json, error) {
 	if err := isValidParams(params); err != nil {
 		return nil, err
 	}
@@ -32,17 +59,25 @@ func (client *TradeClient) Get(path string, params ...map[string]interface{}) (*
 	if params != nil {
 		p = utils.MergeMap(p, params[0])
 	}
-	p = client.signParams("GET", path, p)
+	p, err := client.signParams("GET", path, p)
+	if err != nil {
+		return nil, err
+	}
 	url := client.Endpoint.String() + path
-	return request("GET", url, p)
+	return request(ctx, client.cfg, "GET", url, p)
 }
 
 // HandleGet 将Response解析到obj中
 func (client *TradeClient) HandleGet(path string, obj interface{}, params ...map[string]interface{}) (*simplejson.Json, error) {
+	return client.HandleGetContext(client.context(), path, obj, params...)
+}
+
+// HandleGetContext 可取消的HandleGet
+func (client *TradeClient) HandleGetContext(ctx context.Context, path string, obj interface{}, params ...map[string]interface{}) (*simplejson.Json, error) {
 	if err := utils.CheckPointer(obj); err != nil {
 		return nil, err
 	}
-	resp, err := client.Get(path, params...)
+	resp, err := client.GetContext(ctx, path, params...)
 	if err != nil {
 		return resp, err
 	}
@@ -51,32 +86,53 @@ func (client *TradeClient) HandleGet(path string, obj interface{}, params ...map
 
 // Post Post同步请求
 func (client *TradeClient) Post(path string, params ...map[string]interface{}) (*simplejson.Json, error) {
+	return client.PostContext(client.context(), path, params...)
+}
+
+// PostContext 可取消的Post同步请求
+func (client *TradeClient) PostContext(ctx context.Context, path string, params ...map[string]interface{}) (*simplejson.Json, error) {
 	if err := isValidParams(params); err != nil {
 		return nil, err
 	}
-	p := client.signParams("POST", path, client.sign.GetSignFields())
-	url := client.Endpoint.String() + path + "?" + utils.EncodeQueryString(p)
+	var body map[string]interface{}
 	if params != nil {
-		p = params[0]
-	} else {
-		p = nil
+		body = params[0]
 	}
-	return request("POST", url, p)
+	return client.post(ctx, path, body)
 }
 
 // HandlePost 将Response解析到obj中
 func (client *TradeClient) HandlePost(path string, obj interface{}, params ...map[string]interface{}) (*simplejson.Json, error) {
+	return client.HandlePostContext(client.context(), path, obj, params...)
+}
+
+// HandlePostContext 可取消的HandlePost
+func (client *TradeClient) HandlePostContext(ctx context.Context, path string, obj interface{}, params ...map[string]interface{}) (*simplejson.Json, error) {
 	if err := utils.CheckPointer(obj); err != nil {
 		return nil, err
 	}
-	resp, err := client.Post(path, params...)
+	resp, err := client.PostContext(ctx, path, params...)
 	if err != nil {
 		return resp, err
 	}
 	return utils.Parse2Obj(resp, obj)
 }
 
-func (client *TradeClient) signParams(method, path string, params map[string]interface{}) map[string]interface{} {
-	params["Signature"] = utils.Sign(method, client.Endpoint.Host, path, client.sign.AccessKeySecret, params)
-	return params
+// post 签名后发送任意格式的body，用于批量接口等body为数组的场景
+func (client *TradeClient) post(ctx context.Context, path string, body interface{}) (*simplejson.Json, error) {
+	p, err := client.signParams("POST", path, client.sign.GetSignFields())
+	if err != nil {
+		return nil, err
+	}
+	url := client.Endpoint.String() + path + "?" + utils.EncodeQueryString(p)
+	return request(ctx, client.cfg, "POST", url, body)
+}
+
+func (client *TradeClient) signParams(method, path string, params map[string]interface{}) (map[string]interface{}, error) {
+	signature, err := client.sign.SignRequest(method, client.Endpoint.Host, path, params)
+	if err != nil {
+		return nil, err
+	}
+	params["Signature"] = signature
+	return params, nil
 }
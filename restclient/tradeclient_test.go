@@ -0,0 +1,49 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// slowHandler 账户列表接口在客户端取消请求前不返回，其它接口立即返回
+func slowHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v1/account/accounts" {
+		<-r.Context().Done()
+		return
+	}
+	writeJSON(w, http.StatusOK, `{"status":"ok","data":[]}`)
+}
+
+func TestWithContext(t *testing.T) {
+	ts := newTestServer(slowHandler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.WithContext(ctx).Accounts(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request returned after %v", elapsed)
+	}
+	// 副本的ctx不影响原Client
+	if _, err := client.OpenOrders(&OpenOrdersQuery{}); err != nil {
+		t.Errorf("original client err = %v", err)
+	}
+}
+
+func TestGetContextCanceled(t *testing.T) {
+	ts := newTestServer(slowHandler)
+	defer ts.Close()
+	client, _ := NewMarketClient(testOptions(ts)...)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetContext(ctx, "/market/tickers"); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,162 @@
+package restclient
+
+import (
+	"fmt"
+)
+
+// DepositAddress 充币地址
+type DepositAddress struct {
+	Currency   string `json:"currency"`
+	Address    string `json:"address"`
+	AddressTag string `json:"addressTag"`
+	Chain      string `json:"chain"`
+}
+
+// WithdrawChainQuota 单条链的提币额度
+type WithdrawChainQuota struct {
+	Chain                      string `json:"chain"`
+	MaxWithdrawAmt             string `json:"maxWithdrawAmt"`
+	WithdrawQuotaPerDay        string `json:"withdrawQuotaPerDay"`
+	RemainWithdrawQuotaPerDay  string `json:"remainWithdrawQuotaPerDay"`
+	WithdrawQuotaPerYear       string `json:"withdrawQuotaPerYear"`
+	RemainWithdrawQuotaPerYear string `json:"remainWithdrawQuotaPerYear"`
+	WithdrawQuotaTotal         string `json:"withdrawQuotaTotal"`
+	RemainWithdrawQuotaTotal   string `json:"remainWithdrawQuotaTotal"`
+}
+
+// WithdrawQuota 币种提币额度
+type WithdrawQuota struct {
+	Currency string               `json:"currency"`
+	Chains   []WithdrawChainQuota `json:"chains"`
+}
+
+// WithdrawRequest 提币参数，Address、Currency、Amount 必填
+type WithdrawRequest struct {
+	Address       string
+	AddrTag       string
+	Currency      string
+	Amount        string
+	Fee           string
+	Chain         string
+	ClientOrderID string
+}
+
+func (req *WithdrawRequest) params() map[string]interface{} {
+	params := map[string]interface{}{
+		"address":  req.Address,
+		"currency": req.Currency,
+		"amount":   req.Amount,
+	}
+	setString(params, "addr-tag", req.AddrTag)
+	setString(params, "fee", req.Fee)
+	setString(params, "chain", req.Chain)
+	setString(params, "client-order-id", req.ClientOrderID)
+	return params
+}
+
+// DepositWithdrawQuery 充提记录查询条件，Type 必填
+type DepositWithdrawQuery struct {
+	Type     string // deposit、withdraw
+	Currency string
+	From     int64
+	Size     int
+	Direct   string // prev 升序(默认)、next 降序
+}
+
+func (query *DepositWithdrawQuery) params() map[string]interface{} {
+	params := map[string]interface{}{"type": query.Type}
+	setString(params, "currency", query.Currency)
+	setInt(params, "from", query.From)
+	setInt(params, "size", int64(query.Size))
+	setString(params, "direct", query.Direct)
+	return params
+}
+
+// DepositWithdraw 充提记录
+type DepositWithdraw struct {
+	ID         int64  `json:"id"`
+	Type       string `json:"type"`
+	Currency   string `json:"currency"`
+	Chain      string `json:"chain"`
+	TxHash     string `json:"tx-hash"`
+	Amount     string `json:"amount"`
+	Address    string `json:"address"`
+	AddressTag string `json:"address-tag"`
+	Fee        string `json:"fee"`
+	State      string `json:"state"`
+	ErrorCode  string `json:"error-code"`
+	ErrorMsg   string `json:"error-msg"`
+	CreatedAt  int64  `json:"created-at"`
+	UpdatedAt  int64  `json:"updated-at"`
+}
+
+// DepositAddresses 查询币种的充币地址
+func (client *TradeClient) DepositAddresses(currency string) ([]DepositAddress, error) {
+	var addresses []DepositAddress
+	_, err := client.HandleGet("/v2/account/deposit/address", &addresses,
+		map[string]interface{}{"currency": currency})
+	return addresses, err
+}
+
+// WithdrawQuota 查询币种的提币额度
+func (client *TradeClient) WithdrawQuota(currency string) (*WithdrawQuota, error) {
+	var quota WithdrawQuota
+	_, err := client.HandleGet("/v2/account/withdraw/quota", &quota,
+		map[string]interface{}{"currency": currency})
+	if err != nil {
+		return nil, err
+	}
+	return &quota, nil
+}
+
+// Withdraw 申请提币，返回提币ID
+func (client *TradeClient) Withdraw(req *WithdrawRequest) (int64, error) {
+	var id int64
+	_, err := client.HandlePost("/v1/dw/withdraw/api/create", &id, req.params())
+	return id, err
+}
+
+// CancelWithdraw 撤销提币申请
+func (client *TradeClient) CancelWithdraw(withdrawID int64) error {
+	_, err := client.Post(fmt.Sprintf("/v1/dw/withdraw-virtual/%d/cancel", withdrawID))
+	return err
+}
+
+// DepositWithdraws 查询一页充提记录
+func (client *TradeClient) DepositWithdraws(query *DepositWithdrawQuery) ([]DepositWithdraw, error) {
+	var records []DepositWithdraw
+	_, err := client.HandleGet("/v1/query/deposit-withdraw", &records, query.params())
+	return records, err
+}
+
+// ForEachDepositWithdraw 自动翻页遍历全部充提记录，fn 返回错误时停止遍历并返回该错误
+func (client *TradeClient) ForEachDepositWithdraw(query *DepositWithdrawQuery, fn func(record *DepositWithdraw) error) error {
+	q := *query
+	if q.Direct == "" {
+		q.Direct = "prev"
+	}
+	if q.Size == 0 {
+		q.Size = 100
+	}
+	for {
+		records, err := client.DepositWithdraws(&q)
+		if err != nil {
+			return err
+		}
+		for i := range records {
+			if err := fn(&records[i]); err != nil {
+				return err
+			}
+		}
+		if len(records) < q.Size {
+			return nil
+		}
+		// from 为包含关系，下一页从当前页最后一条记录之后开始
+		last := records[len(records)-1].ID
+		if q.Direct == "prev" {
+			q.From = last + 1
+		} else {
+			q.From = last - 1
+		}
+	}
+}
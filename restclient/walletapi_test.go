@@ -0,0 +1,72 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithdraw(t *testing.T) {
+	api := newFakeAPI(map[string]string{
+		"POST /v1/dw/withdraw/api/create":         `{"status":"ok","data":101123262}`,
+		"POST /v1/dw/withdraw-virtual/101/cancel": `{"status":"ok","data":101}`,
+	})
+	ts := newTestServer(api.handler)
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+
+	id, err := client.Withdraw(&WithdrawRequest{Address: "0xde709f2102306220921060314715629080e2fb77", Currency: "eth", Amount: "0.05", Fee: "0.01", Chain: "eth"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 101123262 {
+		t.Errorf("id = %d", id)
+	}
+	_, _, _, body := api.request()
+	if body["amount"] != "0.05" || body["fee"] != "0.01" || body["chain"] != "eth" {
+		t.Errorf("body = %v", body)
+	}
+	if _, isExist := body["addr-tag"]; isExist {
+		t.Error("empty addr-tag should be omitted")
+	}
+
+	if err := client.CancelWithdraw(101); err != nil {
+		t.Fatal(err)
+	}
+	if method, path, _, _ := api.request(); method != http.MethodPost || path != "/v1/dw/withdraw-virtual/101/cancel" {
+		t.Errorf("request = %s %s", method, path)
+	}
+}
+
+func TestForEachDepositWithdraw(t *testing.T) {
+	// 共5条记录，ID 1-5，from 包含在结果中
+	var froms []string
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		froms = append(froms, query.Get("from"))
+		from := 1
+		fmt.Sscan(query.Get("from"), &from)
+		var records []string
+		for id := from; id <= 5 && len(records) < 2; id++ {
+			records = append(records, fmt.Sprintf(`{"id":%d,"type":"deposit"}`, id))
+		}
+		writeJSON(w, http.StatusOK, `{"status":"ok","data":[`+strings.Join(records, ",")+`]}`)
+	})
+	defer ts.Close()
+	client, _ := NewTradeClient("ak", "sk", testOptions(ts)...)
+	var ids []int64
+	err := client.ForEachDepositWithdraw(&DepositWithdrawQuery{Type: "deposit", Size: 2}, func(record *DepositWithdraw) error {
+		ids = append(ids, record.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[1 2 3 4 5]" {
+		t.Errorf("ids = %v, want [1 2 3 4 5]", ids)
+	}
+	if fmt.Sprint(froms) != "[ 3 5]" {
+		t.Errorf("from = %q, want pages from 3 and 5", froms)
+	}
+}
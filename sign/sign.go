@@ -2,26 +2,48 @@ package sign
 
 import (
 	"time"
+
+	"github.com/feeeei/huobiapi-go/utils"
 )
 
+// Clock 签名时间来源，可用于修正本地时钟偏差
+type Clock interface {
+	Now() time.Time
+}
+
 type Sign struct {
 	AccessKeyID      string
 	AccessKeySecret  string
 	SignatureMethod  string
 	SignatureVersion string
+	Signer           Signer
+	Clock            Clock // 为nil时使用本地时间
 }
 
 func NewSign(accessKeyID, accessKeySecret, version string) *Sign {
+	s := NewSignWithSigner(accessKeyID, NewHmacSigner(accessKeySecret), version)
+	s.AccessKeySecret = accessKeySecret
+	return s
+}
+
+// NewSignWithSigner 使用指定签名算法创建签名
+func NewSignWithSigner(accessKeyID string, signer Signer, version string) *Sign {
 	return &Sign{
 		AccessKeyID:      accessKeyID,
-		AccessKeySecret:  accessKeySecret,
-		SignatureMethod:  "HmacSHA256",
+		SignatureMethod:  signer.Method(),
 		SignatureVersion: version,
+		Signer:           signer,
 	}
 }
 
+// SignRequest 对请求签名，params 需包含 GetSignFields 返回的签名字段
+func (s *Sign) SignRequest(method, host, path string, params map[string]interface{}) (string, error) {
+	payload := method + "\n" + host + "\n" + path + "\n" + utils.EncodeQueryString(params)
+	return s.Signer.Sign(payload)
+}
+
 func (s *Sign) GetSignFields() map[string]interface{} {
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05")
+	timestamp := s.now().UTC().Format("2006-01-02T15:04:05")
 	if s.SignatureVersion == "2.1" {
 		return map[string]interface{}{
 			"accessKey":        s.AccessKeyID,
@@ -39,3 +61,10 @@ func (s *Sign) GetSignFields() map[string]interface{} {
 		"Timestamp":        timestamp,
 	}
 }
+
+func (s *Sign) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return s.Clock.Now()
+}
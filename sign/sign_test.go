@@ -0,0 +1,84 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (clock fixedClock) Now() time.Time { return time.Time(clock) }
+
+func TestSignRequest(t *testing.T) {
+	s := NewSign("e2xxxxxx-99xxxxxx-84xxxxxx-7xxxx", "b0xxxxxx-c6xxxxxx-94xxxxxx-dxxxx", "2")
+	s.Clock = fixedClock(time.Date(2017, 5, 11, 15, 19, 30, 0, time.UTC))
+	params := s.GetSignFields()
+	params["order-id"] = "1234567890"
+	signature, err := s.SignRequest("GET", "api.huobi.pro", "/v1/order/orders", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Nmd8AU8uAe0mkFpxNbiava0aeZzBEtYjCdie1ZYZjoM="; signature != want {
+		t.Errorf("signature = %s, want %s", signature, want)
+	}
+}
+
+func TestGetSignFields(t *testing.T) {
+	clock := fixedClock(time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("UTC+8", 8*3600)))
+	v2 := NewSign("ak", "sk", "2")
+	v2.Clock = clock
+	fields := v2.GetSignFields()
+	if fields["AccessKeyId"] != "ak" || fields["SignatureMethod"] != "HmacSHA256" || fields["Timestamp"] != "2020-01-01T19:04:05" {
+		t.Errorf("v2 fields = %v", fields)
+	}
+	v21 := NewSignWithSigner("ak", NewEd25519Signer(nil), "2.1")
+	v21.Clock = clock
+	fields = v21.GetSignFields()
+	if fields["accessKey"] != "ak" || fields["signatureMethod"] != "Ed25519" || fields["signatureVersion"] != "2.1" {
+		t.Errorf("v2.1 fields = %v", fields)
+	}
+}
+
+func TestEd25519Signer(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	signer, err := LoadEd25519Signer(pemBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := signer.Sign("payload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !ed25519.Verify(public, []byte("payload"), raw) {
+		t.Error("signature does not verify")
+	}
+
+	dir, _ := ioutil.TempDir("", "sign")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "key.pem")
+	ioutil.WriteFile(path, pemBytes, 0600)
+	if _, err := LoadEd25519SignerFile(path); err != nil {
+		t.Error(err)
+	}
+	if _, err := LoadEd25519SignerFile(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Error("missing file should fail")
+	}
+	if _, err := LoadEd25519Signer([]byte("not pem")); err == nil {
+		t.Error("invalid PEM should fail")
+	}
+}
@@ -0,0 +1,313 @@
+// Package signd 签名服务，API Secret 只保存在签名服务进程中，
+// 交易服务通过 Unix socket 请求签名，进程内不持有任何密钥。
+//
+// 协议为每行一个 JSON 请求/响应：
+//
+//	{"op":"info"}                 -> {"accessKey":"...","method":"HmacSHA256"}
+//	{"op":"sign","payload":"..."} -> {"signature":"..."}
+//
+// 出错时响应中包含 error 字段。
+package signd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/sign"
+)
+
+type request struct {
+	Op      string `json:"op"`
+	Payload string `json:"payload,omitempty"`
+}
+
+type response struct {
+	AccessKey string `json:"accessKey,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Server 签名服务端，持有 AccessKeyID 及签名算法
+type Server struct {
+	accessKeyID string
+	signer      sign.Signer
+	listener    net.Listener
+	socketPath  string
+	conns       map[net.Conn]bool
+	closed      bool
+	wg          sync.WaitGroup
+	m           sync.Mutex
+}
+
+// NewServer 创建签名服务
+func NewServer(accessKeyID string, signer sign.Signer) *Server {
+	return &Server{
+		accessKeyID: accessKeyID,
+		signer:      signer,
+		conns:       make(map[net.Conn]bool),
+	}
+}
+
+// ListenAndServe 在 socketPath 上监听并提供签名服务，仅允许同一用户访问。
+// socket 先在权限为 0700 的临时目录中创建并设置为 0600，再移动到 socketPath，避免创建过程中被其它用户连接；
+// socketPath 为遗留的 socket 时删除，为其它类型文件或仍有服务在监听时返回错误
+func (server *Server) ListenAndServe(socketPath string) error {
+	if err := removeStaleSocket(socketPath); err != nil {
+		return err
+	}
+	dir, err := ioutil.TempDir(filepath.Dir(socketPath), ".huobi-signd")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	tmpPath := filepath.Join(dir, "sock")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmpPath, Net: "unix"})
+	if err != nil {
+		return err
+	}
+	// socket 文件移动后由 Close 负责删除
+	listener.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+	// 持锁移动并记录 socket 文件，保证 Close 能看到并删除它
+	server.m.Lock()
+	if server.closed {
+		server.m.Unlock()
+		listener.Close()
+		return fmt.Errorf("Sign server closed")
+	}
+	if err := os.Rename(tmpPath, socketPath); err != nil {
+		server.m.Unlock()
+		listener.Close()
+		return err
+	}
+	server.socketPath = socketPath
+	server.m.Unlock()
+	return server.Serve(listener)
+}
+
+// removeStaleSocket 删除遗留的 socket 文件
+func removeStaleSocket(socketPath string) error {
+	info, err := os.Lstat(socketPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s already exists and is not a socket", socketPath)
+	}
+	if conn, err := net.DialTimeout("unix", socketPath, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is already in use", socketPath)
+	}
+	return os.Remove(socketPath)
+}
+
+// Serve 在 listener 上提供签名服务，阻塞直到 Close
+func (server *Server) Serve(listener net.Listener) error {
+	server.m.Lock()
+	if server.closed {
+		server.m.Unlock()
+		listener.Close()
+		return fmt.Errorf("Sign server closed")
+	}
+	server.listener = listener
+	server.m.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			server.m.Lock()
+			closed := server.closed
+			server.m.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		server.m.Lock()
+		server.conns[conn] = true
+		server.m.Unlock()
+		server.wg.Add(1)
+		go server.serveConn(conn)
+	}
+}
+
+// Close 停止监听并关闭所有连接，删除 ListenAndServe 创建的 socket 文件
+func (server *Server) Close() error {
+	server.m.Lock()
+	server.closed = true
+	// 先删除 socket 文件再关闭监听，避免 Serve 返回后进程退出时文件残留
+	if server.socketPath != "" {
+		if info, e := os.Lstat(server.socketPath); e == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(server.socketPath)
+		}
+		server.socketPath = ""
+	}
+	var err error
+	if server.listener != nil {
+		err = server.listener.Close()
+	}
+	for conn := range server.conns {
+		conn.Close()
+	}
+	server.m.Unlock()
+	server.wg.Wait()
+	return err
+}
+
+func (server *Server) serveConn(conn net.Conn) {
+	defer server.wg.Done()
+	defer func() {
+		server.m.Lock()
+		delete(server.conns, conn)
+		server.m.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req request
+		var resp response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp = server.handle(&req)
+		}
+		if err := encoder.Encode(&resp); err != nil {
+			return
+		}
+	}
+}
+
+func (server *Server) handle(req *request) response {
+	switch req.Op {
+	case "info":
+		return response{AccessKey: server.accessKeyID, Method: server.signer.Method()}
+	case "sign":
+		signature, err := server.signer.Sign(req.Payload)
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{Signature: signature}
+	}
+	return response{Error: fmt.Sprintf("Unknown op %q", req.Op)}
+}
+
+// Client 签名服务客户端，实现 sign.Signer，可通过 config.WithSigner 传入各交易Client
+type Client struct {
+	socketPath  string
+	timeout     time.Duration
+	accessKeyID string
+	method      string
+	conn        net.Conn
+	reader      *bufio.Reader
+	m           sync.Mutex
+}
+
+// Dial 连接签名服务并获取 AccessKeyID 与签名方法，timeout 为单次请求超时，为0时不超时
+func Dial(socketPath string, timeout time.Duration) (*Client, error) {
+	client := &Client{socketPath: socketPath, timeout: timeout}
+	resp, err := client.call(&request{Op: "info"})
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	client.accessKeyID = resp.AccessKey
+	client.method = resp.Method
+	return client, nil
+}
+
+// AccessKeyID 签名服务持有的 AccessKeyID
+func (client *Client) AccessKeyID() string {
+	return client.accessKeyID
+}
+
+// Method 签名方法
+func (client *Client) Method() string {
+	return client.method
+}
+
+// Sign 请求签名服务签名
+func (client *Client) Sign(payload string) (string, error) {
+	resp, err := client.call(&request{Op: "sign", Payload: payload})
+	if err != nil {
+		return "", err
+	}
+	return resp.Signature, nil
+}
+
+// Close 关闭与签名服务的连接
+func (client *Client) Close() error {
+	client.m.Lock()
+	defer client.m.Unlock()
+	return client.closeConn()
+}
+
+// call 发送请求并等待响应，连接异常时关闭连接，下次请求重新连接
+func (client *Client) call(req *request) (*response, error) {
+	client.m.Lock()
+	defer client.m.Unlock()
+	if client.conn == nil {
+		conn, err := net.DialTimeout("unix", client.socketPath, client.dialTimeout())
+		if err != nil {
+			return nil, err
+		}
+		client.conn = conn
+		client.reader = bufio.NewReader(conn)
+	}
+	if client.timeout > 0 {
+		client.conn.SetDeadline(time.Now().Add(client.timeout))
+	}
+
+	b, _ := json.Marshal(req)
+	if _, err := client.conn.Write(append(b, '\n')); err != nil {
+		client.closeConn()
+		return nil, err
+	}
+	line, err := client.reader.ReadBytes('\n')
+	if err != nil {
+		client.closeConn()
+		return nil, err
+	}
+	var resp response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		client.closeConn()
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("Sign server error: %s", resp.Error)
+	}
+	return &resp, nil
+}
+
+func (client *Client) dialTimeout() time.Duration {
+	if client.timeout > 0 {
+		return client.timeout
+	}
+	return 5 * time.Second
+}
+
+func (client *Client) closeConn() error {
+	if client.conn == nil {
+		return nil
+	}
+	err := client.conn.Close()
+	client.conn, client.reader = nil, nil
+	return err
+}
@@ -0,0 +1,150 @@
+package signd
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/sign"
+)
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "signd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// startServer 启动签名服务并等待 socket 可连接
+func startServer(t *testing.T, socketPath string) (*Server, chan error) {
+	t.Helper()
+	server := NewServer("ak", sign.NewHmacSigner("secret"))
+	result := make(chan error, 1)
+	go func() { result <- server.ListenAndServe(socketPath) }()
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("unix", socketPath); err == nil {
+			conn.Close()
+			return server, result
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("server not started")
+	return nil, nil
+}
+
+func TestSign(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "signd.sock")
+	server, result := startServer(t, socketPath)
+
+	info, err := os.Lstat(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0600 {
+		t.Errorf("socket mode = %v, want socket 0600", info.Mode())
+	}
+
+	client, err := Dial(socketPath, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	local := sign.NewHmacSigner("secret")
+	if client.AccessKeyID() != "ak" || client.Method() != local.Method() {
+		t.Errorf("info = %s %s", client.AccessKeyID(), client.Method())
+	}
+	signature, err := client.Sign("GET\napi.huobi.pro\n/v1/account/accounts\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := local.Sign("GET\napi.huobi.pro\n/v1/account/accounts\n")
+	if signature != want {
+		t.Errorf("signature = %s, want %s", signature, want)
+	}
+
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-result; err != nil {
+		t.Errorf("ListenAndServe = %v", err)
+	}
+	if _, err := os.Lstat(socketPath); !os.IsNotExist(err) {
+		t.Errorf("socket not removed: %v", err)
+	}
+	entries, _ := ioutil.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("leftover files: %d", len(entries))
+	}
+	// 服务关闭后客户端重新连接失败
+	if _, err := client.Sign("payload"); err == nil {
+		t.Error("Sign after server Close should fail")
+	}
+}
+
+func TestListenExistingPath(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "signd.sock")
+
+	// 普通文件不会被删除
+	ioutil.WriteFile(socketPath, []byte("data"), 0600)
+	err := NewServer("ak", sign.NewHmacSigner("secret")).ListenAndServe(socketPath)
+	if err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Errorf("ListenAndServe on regular file = %v", err)
+	}
+	if b, _ := ioutil.ReadFile(socketPath); string(b) != "data" {
+		t.Error("regular file was modified")
+	}
+	os.Remove(socketPath)
+
+	// 仍在使用的 socket 不会被替换
+	server, result := startServer(t, socketPath)
+	err = NewServer("ak", sign.NewHmacSigner("other")).ListenAndServe(socketPath)
+	if err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("ListenAndServe on socket in use = %v", err)
+	}
+	server.Close()
+	<-result
+
+	// 遗留的 socket 被删除后重新监听
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: socketPath, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener.SetUnlinkOnClose(false)
+	listener.Close()
+	server, result = startServer(t, socketPath)
+	server.Close()
+	if err := <-result; err != nil {
+		t.Errorf("ListenAndServe on stale socket = %v", err)
+	}
+}
+
+func TestCloseBeforeListen(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "signd.sock")
+	server := NewServer("ak", sign.NewHmacSigner("secret"))
+	server.Close()
+	if err := server.ListenAndServe(socketPath); err == nil {
+		t.Error("ListenAndServe after Close should fail")
+	}
+	if _, err := os.Lstat(socketPath); !os.IsNotExist(err) {
+		t.Errorf("socket left behind: %v", err)
+	}
+}
+
+func TestUnknownOp(t *testing.T) {
+	server := NewServer("ak", sign.NewHmacSigner("secret"))
+	if resp := server.handle(&request{Op: "delete"}); resp.Error == "" {
+		t.Error("unknown op should return error")
+	}
+}
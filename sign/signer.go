@@ -0,0 +1,86 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/feeeei/huobiapi-go/utils"
+)
+
+// Signer 签名算法，对待签名字符串签名并返回 base64 编码的签名
+type Signer interface {
+	// Method 签名方法，对应请求中的 SignatureMethod
+	Method() string
+	// Sign 对待签名字符串签名
+	Sign(payload string) (string, error)
+}
+
+// HmacSigner HmacSHA256 签名，使用 API Secret Key
+type HmacSigner struct {
+	secret string
+}
+
+// NewHmacSigner 创建 HmacSHA256 签名
+func NewHmacSigner(accessKeySecret string) *HmacSigner {
+	return &HmacSigner{secret: accessKeySecret}
+}
+
+// Method 签名方法
+func (signer *HmacSigner) Method() string {
+	return "HmacSHA256"
+}
+
+// Sign HMAC SHA256 签名
+func (signer *HmacSigner) Sign(payload string) (string, error) {
+	return utils.ComputeHmac256(payload, signer.secret), nil
+}
+
+// Ed25519Signer Ed25519 签名，使用非对称 API Key 的私钥
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewEd25519Signer 使用私钥创建 Ed25519 签名
+func NewEd25519Signer(key ed25519.PrivateKey) *Ed25519Signer {
+	return &Ed25519Signer{key: key}
+}
+
+// LoadEd25519Signer 从 PEM 格式（PKCS#8）的私钥创建 Ed25519 签名
+func LoadEd25519Signer(pemBytes []byte) (*Ed25519Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("Invalid PEM private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ed25519Key, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("Private key is not Ed25519")
+	}
+	return NewEd25519Signer(ed25519Key), nil
+}
+
+// LoadEd25519SignerFile 从 PEM 格式的私钥文件创建 Ed25519 签名
+func LoadEd25519SignerFile(path string) (*Ed25519Signer, error) {
+	pemBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadEd25519Signer(pemBytes)
+}
+
+// Method 签名方法
+func (signer *Ed25519Signer) Method() string {
+	return "Ed25519"
+}
+
+// Sign Ed25519 签名
+func (signer *Ed25519Signer) Sign(payload string) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(signer.key, []byte(payload))), nil
+}
@@ -49,13 +49,6 @@ func sortKeys(keys []string) []string {
 	return keys
 }
 
-// Sign 签名请求
-func Sign(method, host, path, secretKey string, params map[string]interface{}) string {
-	var str = method + "\n" + host + "\n" + path + "\n"
-	str += EncodeQueryString(params)
-	return ComputeHmac256(str, secretKey)
-}
-
 // ComputeHmac256 HMAC SHA256加密
 func ComputeHmac256(str string, secret string) string {
 	key := []byte(secret)
@@ -101,7 +94,12 @@ func CheckPointer(obj interface{}) error {
 
 // Parse2Obj 将json解析到obj中
 func Parse2Obj(resp *simplejson.Json, obj interface{}) (*simplejson.Json, error) {
-	d, err := resp.Get("data").Encode()
+	return ParseField2Obj(resp, "data", obj)
+}
+
+// ParseField2Obj 将json中指定字段解析到obj中
+func ParseField2Obj(resp *simplejson.Json, field string, obj interface{}) (*simplejson.Json, error) {
+	d, err := resp.Get(field).Encode()
 	if err != nil {
 		return resp, err
 	}
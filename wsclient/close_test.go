@@ -0,0 +1,102 @@
+package wsclient
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+)
+
+func noop(string, *simplejson.Json) {}
+
+// waitGoroutines 等待goroutine数量回落到 limit 以内
+func waitGoroutines(t *testing.T, limit int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > limit {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			n := runtime.Stack(buf, true)
+			t.Fatalf("goroutines = %d, want <= %d\n%s", runtime.NumGoroutine(), limit, buf[:n])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClose(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	before := runtime.NumGoroutine()
+	market, err := NewMarketWSClient(ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trade, err := NewTradeWSClient("ak", "sk", ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tradeV2, err := NewTradeWSV2Client("ak", "sk", ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := market.Subscribe("market.btcusdt.kline.1min", noop); err != nil {
+		t.Fatal(err)
+	}
+	if err := trade.Subscribe("orders.btcusdt", noop); err != nil {
+		t.Fatal(err)
+	}
+	if err := tradeV2.Subscribe("orders#btcusdt", noop); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, closer := range []interface{ Close() error }{market, trade, tradeV2, market} {
+		if err := closer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := market.Subscribe("market.btcusdt.detail", noop); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Subscribe after Close = %v, want ErrClientClosed", err)
+	}
+	if _, err := trade.Request("accounts.list"); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Request after Close = %v, want ErrClientClosed", err)
+	}
+	if err := tradeV2.Subscribe("orders#ethusdt", noop); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Subscribe after Close = %v, want ErrClientClosed", err)
+	}
+	if state := market.State(); state != StateClosed {
+		t.Errorf("State = %v, want closed", state)
+	}
+	waitGoroutines(t, before+2)
+}
+
+func TestCloseAfterAuthFailed(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	atomic.StoreInt32(&ts.authFail, 1)
+	before := runtime.NumGoroutine()
+	if _, err := NewTradeWSV2Client("ak", "sk", ts.options()...); err == nil {
+		t.Fatal("want auth error")
+	}
+	waitGoroutines(t, before+2)
+}
+
+func TestCloseDuringHandshake(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	market, err := NewMarketWSClient(ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&ts.hang, 1)
+	ts.dropAll()
+	// 等待重连进入握手
+	time.Sleep(200 * time.Millisecond)
+	start := time.Now()
+	market.Close()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close took %v during handshake", elapsed)
+	}
+}
@@ -0,0 +1,65 @@
+package wsclient
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/feeeei/huobiapi-go/apierror"
+)
+
+func TestMarketSubscribeError(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	market, err := NewMarketWSClient(ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer market.Close()
+	atomic.StoreInt32(&ts.subError, 1)
+	err = market.Subscribe("market.btcusdt.kline.1min", noop)
+	apiErr, ok := apierror.AsAPIError(err)
+	if !ok {
+		t.Fatalf("Subscribe = %v, want APIError", err)
+	}
+	if apiErr.Source != apierror.SourceWebSocket || apiErr.ErrCode != "bad-request" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if apiErr.Endpoint != "market.btcusdt.kline.1min" {
+		t.Errorf("Endpoint = %q, want topic", apiErr.Endpoint)
+	}
+}
+
+func TestHandshakeError(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	atomic.StoreInt32(&ts.reject, http.StatusTooManyRequests)
+	_, err := NewTradeWSV2Client("ak", "sk", ts.options()...)
+	apiErr, ok := apierror.AsAPIError(err)
+	if !ok {
+		t.Fatalf("NewTradeWSV2Client = %v, want APIError", err)
+	}
+	if apiErr.Source != apierror.SourceWebSocketTradeV2 || apiErr.HTTPStatus != http.StatusTooManyRequests {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if !strings.Contains(string(apiErr.Body), "rejected") {
+		t.Errorf("Body = %q", apiErr.Body)
+	}
+	if !errors.Is(err, apierror.ErrRateLimited) {
+		t.Error("want rate limited")
+	}
+}
+
+func TestAuthError(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	atomic.StoreInt32(&ts.authFail, 1)
+	if _, err := NewTradeWSClient("ak", "sk", ts.options()...); !apierror.IsSignatureInvalid(err) {
+		t.Errorf("v1 auth = %v, want signature invalid", err)
+	}
+	if _, err := NewTradeWSV2Client("ak", "sk", ts.options()...); !apierror.IsSignatureInvalid(err) {
+		t.Errorf("v2 auth = %v, want signature invalid", err)
+	}
+}
@@ -0,0 +1,168 @@
+package wsclient
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+// eventRecorder 按顺序记录连接状态事件
+type eventRecorder struct {
+	m      sync.Mutex
+	events []string
+	signal chan string
+}
+
+func newEventRecorder() *eventRecorder {
+	return &eventRecorder{signal: make(chan string, 64)}
+}
+
+func (recorder *eventRecorder) add(event string) {
+	recorder.m.Lock()
+	recorder.events = append(recorder.events, event)
+	recorder.m.Unlock()
+	recorder.signal <- event
+}
+
+func (recorder *eventRecorder) snapshot() []string {
+	recorder.m.Lock()
+	defer recorder.m.Unlock()
+	return append([]string(nil), recorder.events...)
+}
+
+// wait 等待指定事件，超时则测试失败
+func (recorder *eventRecorder) wait(t *testing.T, event string) {
+	t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case got := <-recorder.signal:
+			if got == event {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no %s event, got %v", event, recorder.snapshot())
+		}
+	}
+}
+
+func (recorder *eventRecorder) hooks() config.Hooks {
+	return config.Hooks{
+		OnConnected:         func() { recorder.add("connected") },
+		OnDisconnected:      func(err error) { recorder.add("disconnected") },
+		OnReconnecting:      func(attempt int) { recorder.add("reconnecting") },
+		OnReconnected:       func() { recorder.add("reconnected") },
+		OnAuthFailed:        func(err error) { recorder.add("authfailed") },
+		OnResubscribeFailed: func(topic string, err error) { recorder.add("resubscribefailed:" + topic) },
+		OnReconnectGaveUp:   func(err error) { recorder.add("gaveup") },
+	}
+}
+
+func TestHooksOrder(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	recorder := newEventRecorder()
+	client, err := NewTradeWSV2Client("ak", "sk", ts.options(config.WithHooks(recorder.hooks()))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state := client.State(); state != StateConnected {
+		t.Fatalf("state = %v, want StateConnected", state)
+	}
+	if err := client.Subscribe("orders#btcusdt", noop); err != nil {
+		t.Fatal(err)
+	}
+	recorder.wait(t, "connected")
+
+	ts.dropAll()
+	recorder.wait(t, "reconnected")
+	if state := client.State(); state != StateConnected {
+		t.Errorf("state = %v, want StateConnected", state)
+	}
+	client.Close()
+	recorder.wait(t, "disconnected")
+	if state := client.State(); state != StateClosed {
+		t.Errorf("state = %v, want StateClosed", state)
+	}
+
+	want := []string{"connected", "disconnected", "reconnecting", "connected", "reconnected", "disconnected"}
+	if got := recorder.snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+func TestHooksResubscribeFailed(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	recorder := newEventRecorder()
+	client, err := NewTradeWSV2Client("ak", "sk", ts.options(config.WithHooks(recorder.hooks()), config.WithRequestTimeout(100*time.Millisecond))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := client.Subscribe("orders#btcusdt", noop); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&ts.dropSub, 1)
+	ts.dropAll()
+	recorder.wait(t, "resubscribefailed:orders#btcusdt")
+	recorder.wait(t, "reconnected")
+}
+
+func TestHooksAuthFailed(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	atomic.StoreInt32(&ts.authFail, 1)
+	recorder := newEventRecorder()
+	if _, err := NewTradeWSClient("ak", "sk", ts.options(config.WithHooks(recorder.hooks()))...); err == nil {
+		t.Fatal("want auth error")
+	}
+	recorder.wait(t, "disconnected")
+	for _, event := range recorder.snapshot() {
+		if event == "connected" {
+			t.Errorf("events = %v, OnConnected fired before auth", recorder.snapshot())
+		}
+	}
+	if got := recorder.snapshot(); len(got) == 0 || got[0] != "authfailed" {
+		t.Errorf("events = %v, want authfailed first", got)
+	}
+}
+
+func TestCloseFromHook(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	var client *TradeWSV2Client
+	ready := make(chan struct{})
+	closed := make(chan error, 1)
+	hooks := config.Hooks{
+		OnDisconnected: func(err error) {
+			<-ready
+			if errors.Is(err, ErrClientClosed) {
+				return
+			}
+			closed <- client.Close()
+		},
+	}
+	client, err := NewTradeWSV2Client("ak", "sk", ts.options(config.WithHooks(hooks))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(ready)
+	ts.dropAll()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Close from OnDisconnected deadlocked")
+	}
+	if state := client.State(); state != StateClosed {
+		t.Errorf("state = %v, want StateClosed", state)
+	}
+}
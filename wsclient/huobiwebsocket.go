@@ -1,78 +1,208 @@
 package wsclient
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
 	"net/url"
 	"sync"
 	"time"
 
 	"github.com/bitly/go-simplejson"
-	"github.com/feeeei/huobiapi-go/debug"
+	"github.com/feeeei/huobiapi-go/apierror"
+	"github.com/feeeei/huobiapi-go/config"
 	"github.com/feeeei/huobiapi-go/utils"
 	"github.com/gorilla/websocket"
 )
 
+var (
+	// ErrConnectionClosed 连接已断开，等待中的订阅、请求及鉴权均返回该错误
+	ErrConnectionClosed = errors.New("websocket connection closed")
+	// ErrRequestTimeout 订阅、请求或鉴权在 RequestTimeout 内未收到响应
+	ErrRequestTimeout = errors.New("websocket request timeout")
+	// ErrClientClosed Client 已调用 Close，不能再使用
+	ErrClientClosed = errors.New("websocket client closed")
+	// ErrReconnectGaveUp 按重连策略放弃重连
+	ErrReconnectGaveUp = errors.New("websocket reconnect gave up")
+)
+
+// closeTimeout 关闭连接时发送 close 帧的超时时间
+const closeTimeout = time.Second
+
 type Subscriber func(topic string, json *simplejson.Json)
 type aliver interface {
 	ping() map[string]interface{}
 }
 type wsclient interface {
+	// connect 建立连接并完成鉴权
 	connect() error
 	handle(json *simplejson.Json)
-	Subscribe(topic string, listener Subscriber) error
-	UnSubscribe(topic string)
-	Reconnect()
-	SetAutoReconnect(autoReconnect bool)
+	// sendSubscribe 向服务端发送订阅请求并等待结果，用于重连后恢复订阅
+	sendSubscribe(ctx context.Context, topic string) error
 }
 
+// huobiWebSocket 在 Client 整个生命周期内保持不变，重连时只替换底层连接
 type huobiWebSocket struct {
+	cfg           *config.Config
 	url           *url.URL
+	source        apierror.Source // 错误来源接口，用于 APIError 分类
 	ws            *websocket.Conn
 	subscribers   map[string]Subscriber
+	pending       *pendingCalls
+	hooks         *hookQueue
 	wsclient      wsclient
 	alive         bool
+	state         ConnState
+	reconnecting  bool
 	autoReconnect bool
 	needDecrypt   bool
+	closed        bool
+	quit          chan struct{}
+	wg            sync.WaitGroup
 	m             sync.RWMutex
 }
 
-func newHuobiWebSocket(u *url.URL, wsclient wsclient, autoReconnect, needDecrypt bool) (*huobiWebSocket, error) {
-	client := huobiWebSocket{
+func newHuobiWebSocket(cfg *config.Config, u *url.URL, source apierror.Source, wsclient wsclient, needDecrypt bool) *huobiWebSocket {
+	return &huobiWebSocket{
+		cfg:           cfg,
 		url:           u,
+		source:        source,
 		subscribers:   make(map[string]Subscriber),
+		pending:       newPendingCalls(),
+		hooks:         &hookQueue{},
 		wsclient:      wsclient,
-		autoReconnect: autoReconnect,
+		state:         StateConnecting,
+		autoReconnect: true,
 		needDecrypt:   needDecrypt,
+		quit:          make(chan struct{}),
 	}
-	if err := client.newConnect(); err != nil {
-		return nil, err
-	}
-	return &client, nil
 }
 
 func (client *huobiWebSocket) newConnect() error {
-	client.m.Lock()
-	defer client.m.Unlock()
-	ws, response, err := websocket.DefaultDialer.Dial(client.url.String(), nil)
-	if response == nil || response.StatusCode >= 400 {
-		return fmt.Errorf("Connection not established")
+	header := http.Header{}
+	if client.cfg.UserAgent != "" {
+		header.Set("User-Agent", client.cfg.UserAgent)
+	}
+	ws, response, err := client.dial(header)
+	if client.isClosed() {
+		if ws != nil {
+			ws.Close()
+		}
+		return ErrClientClosed
+	}
+	if response != nil && response.StatusCode >= 400 {
+		// 握手失败时 websocket 库保留了响应内容的前1024字节
+		body, _ := ioutil.ReadAll(response.Body)
+		return &apierror.APIError{
+			Source:     client.source,
+			HTTPStatus: response.StatusCode,
+			Endpoint:   client.url.Path,
+			Body:       body,
+		}
 	}
 	if err != nil {
 		return err
 	}
+	if response == nil {
+		return fmt.Errorf("Connection not established")
+	}
+
+	client.m.Lock()
+	if client.closed {
+		client.m.Unlock()
+		ws.Close()
+		return ErrClientClosed
+	}
 	client.ws = ws
 	client.alive = true
-	go client.handleMessageLoop()
-	debug.Println("WebSocket connected")
+	client.wg.Add(1)
+	go client.handleMessageLoop(ws)
+	client.m.Unlock()
 	return nil
 }
 
-func (client *huobiWebSocket) handleMessageLoop() {
+// connected 连接建立并完成鉴权后调用，修改连接状态并触发 OnConnected，连接已断开时不做处理
+func (client *huobiWebSocket) connected() {
+	client.m.Lock()
+	if !client.alive {
+		client.m.Unlock()
+		return
+	}
+	// 重连过程中保持 StateReconnecting，直至恢复订阅完成
+	if client.state != StateReconnecting && client.state != StateClosed {
+		client.state = StateConnected
+	}
+	client.m.Unlock()
+
+	client.cfg.Logger.Println("WebSocket connected")
+	client.onConnected()
+}
+
+// dial 建立连接，Close 时中断正在进行的连接及握手
+func (client *huobiWebSocket) dial(header http.Header) (*websocket.Conn, *http.Response, error) {
+	dialer := *client.cfg.Dialer
+	netDial := dialer.NetDialContext
+	if netDial == nil && dialer.NetDial != nil {
+		dial := dialer.NetDial
+		netDial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dial(network, addr)
+		}
+	} else if netDial == nil {
+		netDial = (&net.Dialer{}).DialContext
+	}
+
+	// 握手阶段不受 ctx 控制，Close 时直接关闭底层连接
+	var m sync.Mutex
+	var conns []net.Conn
+	quit := false
+	dialer.NetDial = nil
+	dialer.NetDialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := netDial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		m.Lock()
+		defer m.Unlock()
+		if quit {
+			conn.Close()
+			return nil, ErrClientClosed
+		}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-client.quit:
+			cancel()
+			m.Lock()
+			quit = true
+			for _, conn := range conns {
+				conn.Close()
+			}
+			m.Unlock()
+		case <-done:
+			cancel()
+		}
+	}()
+	return dialer.DialContext(ctx, client.url.String(), header)
+}
+
+func (client *huobiWebSocket) handleMessageLoop(ws *websocket.Conn) {
+	defer client.wg.Done()
+	var err error
 	for true {
-		_, rawMessage, err := client.ws.ReadMessage()
+		var rawMessage []byte
+		_, rawMessage, err = ws.ReadMessage()
 		if err != nil {
-			debug.Println("handle message loop error: ", client.autoReconnect, err)
+			client.cfg.Logger.Println("handle message loop error: ", err)
 			break
 		}
 		var message []byte
@@ -82,27 +212,41 @@ func (client *huobiWebSocket) handleMessageLoop() {
 			message = rawMessage
 		}
 		if err != nil {
-			debug.Println("handle message loop error: ", client.autoReconnect, err)
+			client.cfg.Logger.Println("handle message loop error: ", err)
 			break
 		}
-		debug.Println("Receive:", string(message))
+		client.cfg.Logger.Println("Receive:", string(message))
 		json, _ := simplejson.NewJson(message)
 		client.wsclient.handle(json)
 	}
-	if client.autoReconnect {
+	// 连接已被主动关闭或替换时，由关闭方负责后续处理
+	if client.dropConn(ws, err) && client.isAutoReconnect() {
 		client.reconnect()
 	}
 }
 
+// keepAlive 定期发送心跳，发送失败时重新连接，Close 后退出；duration 不大于0时不发送心跳
 func (client *huobiWebSocket) keepAlive(duration time.Duration, heartbeat aliver) {
+	if duration <= 0 {
+		return
+	}
+	client.wg.Add(1)
 	go func() {
-		for client.alive {
-			err := client.sendMessage(heartbeat.ping())
-			client.alive = err == nil
-			if err != nil {
+		defer client.wg.Done()
+		ticker := time.NewTicker(duration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-client.quit:
+				return
+			case <-ticker.C:
+			}
+			if !client.isAlive() {
+				continue
+			}
+			if err := client.sendMessage(heartbeat.ping()); err != nil && client.isAutoReconnect() {
+				client.closeConn(err)
 				client.reconnect()
-			} else {
-				time.Sleep(duration)
 			}
 		}
 	}()
@@ -115,6 +259,24 @@ func (client *huobiWebSocket) subscribe(topic string, listener Subscriber) {
 	client.subscribers[topic] = listener
 }
 
+// listener 获取主题的监听函数，未订阅时返回nil
+func (client *huobiWebSocket) listener(topic string) Subscriber {
+	client.m.RLock()
+	defer client.m.RUnlock()
+	return client.subscribers[topic]
+}
+
+// topics 返回已订阅主题的快照
+func (client *huobiWebSocket) topics() map[string]Subscriber {
+	client.m.RLock()
+	defer client.m.RUnlock()
+	topics := make(map[string]Subscriber, len(client.subscribers))
+	for topic, listener := range client.subscribers {
+		topics[topic] = listener
+	}
+	return topics
+}
+
 // unsubscribe 取消订阅
 func (client *huobiWebSocket) unsubscribe(topic string) {
 	client.m.Lock()
@@ -122,52 +284,248 @@ func (client *huobiWebSocket) unsubscribe(topic string) {
 	delete(client.subscribers, topic)
 }
 
+// isAlive 连接是否可用
+func (client *huobiWebSocket) isAlive() bool {
+	client.m.RLock()
+	defer client.m.RUnlock()
+	return client.alive
+}
+
+// isClosed 是否已调用 close
+func (client *huobiWebSocket) isClosed() bool {
+	client.m.RLock()
+	defer client.m.RUnlock()
+	return client.closed
+}
+
+func (client *huobiWebSocket) isAutoReconnect() bool {
+	client.m.RLock()
+	defer client.m.RUnlock()
+	return client.autoReconnect && !client.closed
+}
+
+func (client *huobiWebSocket) setAutoReconnect(autoReconnect bool) {
+	client.m.Lock()
+	defer client.m.Unlock()
+	client.autoReconnect = autoReconnect
+}
+
+// roundTrip 登记请求、发送 message 并等待响应，ctx 结束、超时或连接断开时提前返回。
+// idField 不为空时将生成的请求ID写入 message 的该字段
+func (client *huobiWebSocket) roundTrip(ctx context.Context, key, idField string, message map[string]interface{}) (*simplejson.Json, error) {
+	call := client.pending.add(key)
+	if idField != "" {
+		message[idField] = call.id
+	}
+	if err := client.sendMessage(message); err != nil {
+		client.pending.remove(call)
+		return nil, err
+	}
+
+	var timeout <-chan time.Time
+	if client.cfg.RequestTimeout > 0 {
+		timer := time.NewTimer(client.cfg.RequestTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case result := <-call.done:
+		return result.json, result.err
+	case <-ctx.Done():
+		client.pending.remove(call)
+		return nil, ctx.Err()
+	case <-timeout:
+		client.pending.remove(call)
+		return nil, fmt.Errorf("%s: %w", key, ErrRequestTimeout)
+	}
+}
+
 // sendMessage 通过Websocket发送request
 func (client *huobiWebSocket) sendMessage(message interface{}) error {
 	b, err := json.Marshal(message)
 	if err != nil {
-		return nil
+		return err
 	}
-	debug.Println("Send message:", string(b))
+	client.cfg.Logger.Println("Send message:", string(b))
 	return client.send(b)
 }
 
 func (client *huobiWebSocket) send(b []byte) error {
 	client.m.Lock()
 	defer client.m.Unlock()
+	if client.closed {
+		return ErrClientClosed
+	}
+	if !client.alive {
+		return ErrConnectionClosed
+	}
 	err := client.ws.WriteMessage(websocket.TextMessage, b)
 	if err != nil {
-		debug.Println("Send message error:", err)
+		client.cfg.Logger.Println("Send message error:", err)
 	}
 	return err
 }
 
-// reconnect 循环式重新链接，如果中途失败会sleep 1s之后继续尝试
+// reconnect 按重连策略循环重新链接，同一时间只有一个重连在执行，Close 后退出
 func (client *huobiWebSocket) reconnect() {
-	success := false
-	for !success {
-		debug.Println("Begin reconnecting")
-		time.Sleep(time.Second * 1)
-		client.close()
+	client.m.Lock()
+	if client.reconnecting || client.closed {
+		client.m.Unlock()
+		return
+	}
+	client.reconnecting = true
+	client.m.Unlock()
+
+	policy := client.cfg.Reconnect
+	if policy == nil {
+		policy = &config.DefaultReconnectBackoff
+	}
+	client.setState(StateReconnecting)
+	start := time.Now()
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		delay, ok := policy.NextDelay(attempt, time.Since(start))
+		if !ok {
+			client.giveUp(fmt.Errorf("%w after %d attempts: %v", ErrReconnectGaveUp, attempt-1, lastErr))
+			return
+		}
+		client.cfg.Logger.Println("Begin reconnecting, attempt:", attempt, "delay:", delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-client.quit:
+			timer.Stop()
+			client.stopReconnecting()
+			return
+		case <-timer.C:
+		}
+		client.closeConn(nil)
+		client.onReconnecting(attempt)
 		if err := client.wsclient.connect(); err != nil {
-			debug.Println("Reconneting error:", err)
+			if errors.Is(err, ErrClientClosed) {
+				client.stopReconnecting()
+				return
+			}
+			client.cfg.Logger.Println("Reconneting error:", err)
+			lastErr = err
 			continue
 		}
-		for topic := range client.subscribers {
-			if err := client.wsclient.Subscribe(topic, client.subscribers[topic]); err != nil {
-				debug.Println("Reconneting subscribe error:", err)
-				continue
-			}
+		client.resubscribe()
+		if client.finishReconnect() {
+			break
 		}
-		success = true
+		if client.isClosed() {
+			client.stopReconnecting()
+			return
+		}
+		// 恢复订阅期间连接再次断开，此时的重连请求已被忽略，由本次重连继续处理
+		client.cfg.Logger.Println("Reconneting error: connection lost while resubscribing")
+		lastErr = ErrConnectionClosed
 	}
-	debug.Println("Reconnecting successful")
-	return
+	client.cfg.Logger.Println("Reconnecting successful")
+	client.onReconnected()
 }
 
-func (client *huobiWebSocket) close() {
+// finishReconnect 连接仍可用时结束重连并返回true，检查连接与清除重连标记在同一临界区内，
+// 之后的断线会重新触发重连
+func (client *huobiWebSocket) finishReconnect() bool {
+	client.m.Lock()
+	defer client.m.Unlock()
+	if !client.alive {
+		return false
+	}
+	client.reconnecting = false
+	client.state = StateConnected
+	return true
+}
+
+// stopReconnecting 清除重连标记
+func (client *huobiWebSocket) stopReconnecting() {
 	client.m.Lock()
 	defer client.m.Unlock()
+	client.reconnecting = false
+}
+
+// giveUp 放弃重连，连接保持断开状态，可调用 Reconnect 重新开始（包括在 OnReconnectGaveUp 中）
+func (client *huobiWebSocket) giveUp(err error) {
+	client.closeConn(nil)
+	client.m.Lock()
+	client.reconnecting = false
+	if client.state != StateClosed {
+		client.state = StateDisconnected
+	}
+	client.m.Unlock()
+	client.cfg.Logger.Println("Reconnecting gave up:", err)
+	client.onReconnectGaveUp(err)
+}
+
+// resubscribe 重连后恢复所有订阅
+func (client *huobiWebSocket) resubscribe() {
+	for topic := range client.topics() {
+		if err := client.wsclient.sendSubscribe(context.Background(), topic); err != nil {
+			client.cfg.Logger.Println("Reconneting subscribe error:", topic, err)
+			client.onResubscribeFailed(topic, err)
+		}
+	}
+}
+
+// closeConn 因 err 关闭当前连接，等待中的请求返回 ErrConnectionClosed，主动重连时 err 为nil
+func (client *huobiWebSocket) closeConn(err error) {
+	client.dropConn(nil, err)
+}
+
+// dropConn 因 err 关闭连接 ws，ws 为nil时关闭当前连接。
+// ws 已被主动关闭或替换时不做处理，返回是否关闭了连接
+func (client *huobiWebSocket) dropConn(ws *websocket.Conn, err error) bool {
+	client.m.Lock()
+	current := client.ws
+	if current == nil || (ws != nil && ws != current) {
+		client.m.Unlock()
+		return false
+	}
+	client.ws = nil
 	client.alive = false
-	client.ws.Close()
+	if client.state == StateConnected || client.state == StateConnecting {
+		client.state = StateDisconnected
+	}
+	client.m.Unlock()
+
+	current.Close()
+	client.pending.failAll(ErrConnectionClosed)
+	client.onDisconnected(err)
+	return true
+}
+
+// close 发送 close 帧并关闭连接，停止心跳及重连，等待所有goroutine退出。
+// 不能在订阅回调中调用，否则会一直等待回调所在的goroutine
+func (client *huobiWebSocket) close() {
+	client.m.Lock()
+	if client.closed {
+		client.m.Unlock()
+		return
+	}
+	client.closed = true
+	close(client.quit)
+	ws := client.ws
+	client.ws = nil
+	client.alive = false
+	client.state = StateClosed
+	if ws != nil {
+		message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		ws.WriteControl(websocket.CloseMessage, message, time.Now().Add(closeTimeout))
+	}
+	client.m.Unlock()
+
+	client.pending.failAll(ErrClientClosed)
+	if ws != nil {
+		ws.Close()
+		client.onDisconnected(ErrClientClosed)
+	}
+	client.wg.Wait()
+	client.cfg.Logger.Println("WebSocket closed")
+}
+
+// encodeBody 将消息编码为原始响应，用于 APIError.Body
+func encodeBody(json *simplejson.Json) []byte {
+	b, _ := json.Encode()
+	return b
 }
@@ -1,63 +1,71 @@
 package wsclient
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/bitly/go-simplejson"
+	"github.com/feeeei/huobiapi-go/apierror"
 	"github.com/feeeei/huobiapi-go/config"
-	"github.com/feeeei/huobiapi-go/debug"
 	"github.com/feeeei/huobiapi-go/utils"
 )
 
 type MarketWSClient struct {
-	ws            *huobiWebSocket
-	subscribeWait map[string]chan error
-	autoReconnect bool
+	cfg *config.Config
+	ws  *huobiWebSocket
 }
 
 // NewMarketWSClient WebSocket格式行情Client
-func NewMarketWSClient() (*MarketWSClient, error) {
-	client := &MarketWSClient{
-		subscribeWait: make(map[string]chan error),
-		autoReconnect: true,
-	}
+func NewMarketWSClient(options ...config.Option) (*MarketWSClient, error) {
+	client := &MarketWSClient{cfg: config.New(options...)}
+	client.ws = newHuobiWebSocket(client.cfg, client.cfg.WsEndpoint, apierror.SourceWebSocket, client, true)
 	if err := client.connect(); err != nil {
+		client.ws.close()
 		return nil, err
 	}
+	client.keepAlive()
 	return client, nil
 }
 
 func (client *MarketWSClient) connect() error {
-	ws, err := newHuobiWebSocket(config.HuobiWsEndpoint, client, client.autoReconnect, true)
-	if err != nil {
+	if err := client.ws.newConnect(); err != nil {
 		return err
 	}
-	client.ws = ws
-	client.keepAlive()
+	client.ws.connected()
 	return nil
 }
 
 // Subscribe 订阅主题
 func (client *MarketWSClient) Subscribe(topic string, listener Subscriber) error {
+	return client.SubscribeContext(context.Background(), topic, listener)
+}
+
+// SubscribeContext 订阅主题，ctx 结束时停止等待订阅结果
+func (client *MarketWSClient) SubscribeContext(ctx context.Context, topic string, listener Subscriber) error {
+	if client.ws.isClosed() {
+		return ErrClientClosed
+	}
 	// 如果已经订阅，直接刷新 listener
-	if client.ws.subscribers[topic] != nil {
+	if client.ws.listener(topic) != nil {
 		client.ws.subscribe(topic, listener)
 		return nil
 	}
 
-	client.ws.sendMessage(map[string]interface{}{"sub": topic, "id": topic})
-	client.subscribeWait[topic] = make(chan error)
-	err := <-client.subscribeWait[topic]
-	if err != nil {
+	if err := client.sendSubscribe(ctx, topic); err != nil {
 		return err
 	}
 	client.ws.subscribe(topic, listener) // 如果订阅成功，再加入监听列表
 	return nil
 }
 
+func (client *MarketWSClient) sendSubscribe(ctx context.Context, topic string) error {
+	message := map[string]interface{}{"sub": topic}
+	_, err := client.ws.roundTrip(ctx, pendingKey("sub", topic), "id", message)
+	return err
+}
+
 // UnSubscribe 取消订阅主题
 func (client *MarketWSClient) UnSubscribe(topic string) {
-	if client.ws.subscribers[topic] == nil {
+	if client.ws.listener(topic) == nil {
 		return
 	}
 
@@ -68,17 +76,35 @@ func (client *MarketWSClient) UnSubscribe(topic string) {
 
 // SetAutoReconnect 设置socket中断时自动重新链接，默认true
 func (client *MarketWSClient) SetAutoReconnect(autoReconnect bool) {
-	client.autoReconnect = autoReconnect
-	client.ws.autoReconnect = autoReconnect
+	client.ws.setAutoReconnect(autoReconnect)
 }
 
-// Reconnect 重新连接，关闭旧链接，建立新链接，重新授权，重新订阅
+// Reconnect 重新连接，关闭旧链接，建立新链接，重新授权，重新订阅。
+// 已有重连在进行时直接返回；放弃自动重连后可调用以重新开始
 func (client *MarketWSClient) Reconnect() {
 	client.ws.reconnect()
 }
 
+// State 当前连接状态
+func (client *MarketWSClient) State() ConnState {
+	return client.ws.currentState()
+}
+
+// Close 取消所有订阅并关闭连接，停止心跳及自动重连，等待后台goroutine退出。
+// 之后的订阅请求均返回 ErrClientClosed，不能在订阅回调中调用
+func (client *MarketWSClient) Close() error {
+	if client.ws.isClosed() {
+		return nil
+	}
+	for topic := range client.ws.topics() {
+		client.UnSubscribe(topic)
+	}
+	client.ws.close()
+	return nil
+}
+
 func (client *MarketWSClient) keepAlive() {
-	client.ws.keepAlive(config.HeartbeatDuration, client)
+	client.ws.keepAlive(client.cfg.HeartbeatDuration, client)
 }
 
 // handle 处理消息
@@ -86,7 +112,7 @@ func (client *MarketWSClient) handle(json *simplejson.Json) {
 	// 处理订阅推送消息
 	if topic, isExist := json.CheckGet("ch"); isExist {
 		topicStr := topic.MustString()
-		subscriber := client.ws.subscribers[topicStr]
+		subscriber := client.ws.listener(topicStr)
 		if subscriber != nil {
 			subscriber(topicStr, json)
 		}
@@ -106,21 +132,28 @@ func (client *MarketWSClient) handle(json *simplejson.Json) {
 
 	// 处理订阅成功消息
 	if topic, isExist := json.CheckGet("subbed"); isExist {
-		client.subscribeWait[topic.MustString()] <- nil
+		id := json.Get("id").MustString()
+		client.ws.pending.resolve(id, pendingKey("sub", topic.MustString()), json, nil)
 		return
 	}
 
 	// 处理取消订阅消息
 	if topic, isExist := json.CheckGet("unsubbed"); isExist {
-		debug.Println("Unsubscribe", topic.MustString(), json.Get("status").MustString())
+		client.cfg.Logger.Println("Unsubscribe", topic.MustString(), json.Get("status").MustString())
 		return
 	}
 
 	// 处理订阅失败消息
 	if json.Get("status").MustString() == "error" {
 		if id, isExist := json.CheckGet("id"); isExist {
-			err := fmt.Errorf(json.Get("err-msg").MustString())
-			client.subscribeWait[id.MustString()] <- err
+			err := &apierror.APIError{
+				Source:   apierror.SourceWebSocket,
+				ErrCode:  json.Get("err-code").MustString(),
+				Message:  json.Get("err-msg").MustString(),
+				Endpoint: client.ws.pending.topic(id.MustString()),
+				Body:     encodeBody(json),
+			}
+			client.ws.pending.resolve(id.MustString(), "", json, err)
 		}
 		return
 	}
@@ -0,0 +1,111 @@
+package wsclient
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bitly/go-simplejson"
+)
+
+// pendingResult 请求的响应结果
+type pendingResult struct {
+	json *simplejson.Json
+	err  error
+}
+
+// pendingCall 等待响应的请求，id 随请求发送（id/cid），key 为请求主题，
+// 响应中不带 id 时（如v2接口）按 key 匹配最早发出的请求
+type pendingCall struct {
+	id   string
+	key  string
+	seq  uint64
+	done chan pendingResult
+}
+
+// pendingCalls 等待响应的请求表，可在多个goroutine中并发使用
+type pendingCalls struct {
+	seq   uint64
+	calls map[string]*pendingCall
+	m     sync.Mutex
+}
+
+func newPendingCalls() *pendingCalls {
+	return &pendingCalls{calls: make(map[string]*pendingCall)}
+}
+
+// add 登记请求并生成请求ID，需在发送请求前调用
+func (pending *pendingCalls) add(key string) *pendingCall {
+	pending.m.Lock()
+	defer pending.m.Unlock()
+	pending.seq++
+	call := &pendingCall{
+		id:   strconv.FormatUint(pending.seq, 10),
+		key:  key,
+		seq:  pending.seq,
+		done: make(chan pendingResult, 1),
+	}
+	pending.calls[call.id] = call
+	return call
+}
+
+// remove 移除请求，用于等待方放弃等待或请求发送失败
+func (pending *pendingCalls) remove(call *pendingCall) {
+	pending.m.Lock()
+	defer pending.m.Unlock()
+	delete(pending.calls, call.id)
+}
+
+// resolve 将响应交给对应的请求，响应不带 id 时按 key 匹配最早的请求，返回是否找到请求
+func (pending *pendingCalls) resolve(id, key string, json *simplejson.Json, err error) bool {
+	pending.m.Lock()
+	call, isExist := pending.calls[id]
+	if !isExist && id == "" && key != "" {
+		for _, c := range pending.calls {
+			if c.key == key && (call == nil || c.seq < call.seq) {
+				call = c
+			}
+		}
+	}
+	if call != nil {
+		delete(pending.calls, call.id)
+	}
+	pending.m.Unlock()
+
+	if call == nil {
+		return false
+	}
+	call.done <- pendingResult{json: json, err: err}
+	return true
+}
+
+// topic 返回请求ID对应请求的主题，请求不存在时返回空
+func (pending *pendingCalls) topic(id string) string {
+	pending.m.Lock()
+	defer pending.m.Unlock()
+	call, isExist := pending.calls[id]
+	if !isExist {
+		return ""
+	}
+	if i := strings.Index(call.key, ":"); i >= 0 {
+		return call.key[i+1:]
+	}
+	return call.key
+}
+
+// failAll 以 err 结束所有等待中的请求，用于连接断开
+func (pending *pendingCalls) failAll(err error) {
+	pending.m.Lock()
+	calls := pending.calls
+	pending.calls = make(map[string]*pendingCall)
+	pending.m.Unlock()
+
+	for _, call := range calls {
+		call.done <- pendingResult{err: err}
+	}
+}
+
+// pendingKey 生成按主题匹配响应时使用的 key
+func pendingKey(op, topic string) string {
+	return op + ":" + topic
+}
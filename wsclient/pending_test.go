@@ -0,0 +1,140 @@
+package wsclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+)
+
+func receive(t *testing.T, call *pendingCall) pendingResult {
+	t.Helper()
+	select {
+	case result := <-call.done:
+		return result
+	case <-time.After(time.Second):
+		t.Fatal("no result")
+	}
+	return pendingResult{}
+}
+
+func TestPendingResolveByID(t *testing.T) {
+	pending := newPendingCalls()
+	first := pending.add(pendingKey("sub", "a"))
+	second := pending.add(pendingKey("sub", "a"))
+	if first.id == second.id {
+		t.Fatal("ids should be unique")
+	}
+	json := simplejson.New()
+	if !pending.resolve(second.id, "", json, nil) {
+		t.Fatal("resolve by id failed")
+	}
+	if result := receive(t, second); result.json != json || result.err != nil {
+		t.Errorf("result = %+v", result)
+	}
+	if pending.resolve(second.id, "", json, nil) {
+		t.Error("resolved twice")
+	}
+	if got := pending.topic(first.id); got != "a" {
+		t.Errorf("topic = %q, want a", got)
+	}
+}
+
+func TestPendingResolveByKey(t *testing.T) {
+	pending := newPendingCalls()
+	calls := make([]*pendingCall, 3)
+	for i := range calls {
+		calls[i] = pending.add(pendingKey("sub", "orders#btcusdt"))
+	}
+	other := pending.add(pendingKey("sub", "accounts.update#0"))
+	// 不带 id 的响应按发出顺序匹配同一主题的请求
+	for i, call := range calls {
+		err := fmt.Errorf("response %d", i)
+		if !pending.resolve("", pendingKey("sub", "orders#btcusdt"), nil, err) {
+			t.Fatalf("resolve %d failed", i)
+		}
+		if result := receive(t, call); result.err != err {
+			t.Errorf("call %d got %v", i, result.err)
+		}
+	}
+	if pending.resolve("", pendingKey("sub", "orders#btcusdt"), nil, nil) {
+		t.Error("resolved without pending call")
+	}
+	pending.remove(other)
+	if pending.resolve(other.id, "", nil, nil) {
+		t.Error("resolved removed call")
+	}
+}
+
+func TestPendingFailAll(t *testing.T) {
+	pending := newPendingCalls()
+	calls := []*pendingCall{pending.add("a"), pending.add("b")}
+	pending.failAll(ErrConnectionClosed)
+	for _, call := range calls {
+		if result := receive(t, call); !errors.Is(result.err, ErrConnectionClosed) {
+			t.Errorf("result = %v", result.err)
+		}
+	}
+	if pending.resolve(calls[0].id, "", nil, nil) {
+		t.Error("resolved after failAll")
+	}
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	trade, err := NewTradeWSClient("ak", "sk", ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trade.Close()
+	tradeV2, err := NewTradeWSV2Client("ak", "sk", ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tradeV2.Close()
+	market, err := NewMarketWSClient(ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer market.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			// 测试服务将 cid 作为 data 返回，用于校验响应交给了对应的请求
+			json, err := trade.Request("accounts.list")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if json.Get("cid").MustString() != json.Get("data").MustString() {
+				t.Errorf("response for %s delivered to %s", json.Get("data").MustString(), json.Get("cid").MustString())
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			if err := trade.Subscribe(fmt.Sprint("orders.", i), noop); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			if err := tradeV2.Subscribe(fmt.Sprint("orders#", i%3), noop); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			if err := market.Subscribe(fmt.Sprint("market.", i, ".detail"), noop); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
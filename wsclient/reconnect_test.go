@@ -0,0 +1,135 @@
+package wsclient
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+// waitState 等待连接进入指定状态，超时则测试失败
+func waitState(t *testing.T, state func() ConnState, want ConnState) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for state() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("state = %v, want %v", state(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReconnectSingleFlight(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	client, err := NewMarketWSClient(ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	for i := 0; i < 3; i++ {
+		before := atomic.LoadInt32(&ts.accepts)
+		ts.dropAll()
+		go client.Reconnect()
+		go client.Reconnect()
+		time.Sleep(50 * time.Millisecond)
+		waitState(t, client.State, StateConnected)
+		time.Sleep(50 * time.Millisecond)
+		if got := atomic.LoadInt32(&ts.accepts) - before; got != 1 {
+			t.Fatalf("round %d: %d connections, want 1", i, got)
+		}
+	}
+}
+
+func TestReconnectResubscribe(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	recorder := newEventRecorder()
+	client, err := NewMarketWSClient(ts.options(config.WithHooks(recorder.hooks()))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := client.Subscribe("market.btcusdt.kline.1min", noop); err != nil {
+		t.Fatal(err)
+	}
+	// 恢复订阅时连接再次断开，应继续重连直至订阅恢复
+	atomic.StoreInt32(&ts.killSub, 1)
+	ts.dropAll()
+	recorder.wait(t, "reconnected")
+	if state := client.State(); state != StateConnected {
+		t.Errorf("state = %v, want StateConnected", state)
+	}
+	if !client.ws.isAlive() {
+		t.Error("connection not alive after reconnect")
+	}
+	if got := atomic.LoadInt32(&ts.accepts); got < 3 {
+		t.Errorf("%d connections, want reconnect after drop during resubscribe", got)
+	}
+}
+
+func TestReconnectGaveUp(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	gaveUp := make(chan error, 1)
+	policy := &config.ReconnectBackoff{BaseDelay: 10 * time.Millisecond, MaxAttempts: 2}
+	hooks := config.Hooks{OnReconnectGaveUp: func(err error) { gaveUp <- err }}
+	client, err := NewMarketWSClient(ts.options(config.WithReconnect(policy), config.WithHooks(hooks))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	atomic.StoreInt32(&ts.reject, 503)
+	ts.dropAll()
+	select {
+	case err := <-gaveUp:
+		if !errors.Is(err, ErrReconnectGaveUp) {
+			t.Errorf("err = %v, want ErrReconnectGaveUp", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("reconnect not given up")
+	}
+	waitState(t, client.State, StateDisconnected)
+
+	// 放弃后可手动重新开始重连
+	atomic.StoreInt32(&ts.reject, 0)
+	client.Reconnect()
+	waitState(t, client.State, StateConnected)
+}
+
+func TestReconnectFromGaveUpHook(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	var client *MarketWSClient
+	ready := make(chan struct{})
+	var gaveUp int32
+	restarted := make(chan struct{})
+	policy := &config.ReconnectBackoff{BaseDelay: 10 * time.Millisecond, MaxAttempts: 1}
+	hooks := config.Hooks{OnReconnectGaveUp: func(error) {
+		<-ready
+		if atomic.AddInt32(&gaveUp, 1) == 1 {
+			atomic.StoreInt32(&ts.reject, 0)
+			client.Reconnect()
+			close(restarted)
+		}
+	}}
+	client, err := NewMarketWSClient(ts.options(config.WithReconnect(policy), config.WithHooks(hooks))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	close(ready)
+	atomic.StoreInt32(&ts.reject, 503)
+	ts.dropAll()
+	select {
+	case <-restarted:
+	case <-time.After(3 * time.Second):
+		t.Fatal("reconnect not given up")
+	}
+	waitState(t, client.State, StateConnected)
+	if got := atomic.LoadInt32(&gaveUp); got != 1 {
+		t.Errorf("gave up %d times, want 1", got)
+	}
+}
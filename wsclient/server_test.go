@@ -0,0 +1,143 @@
+package wsclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/config"
+	"github.com/gorilla/websocket"
+)
+
+// testServer 本地 WebSocket 服务，按路径模拟行情、v1 交易及 v2 交易接口
+type testServer struct {
+	server   *httptest.Server
+	conns    sync.Map
+	accepts  int32 // 已建立的连接数
+	dropSub  int32 // 不为0时不响应订阅
+	killSub  int32 // 收到订阅时断开连接的剩余次数
+	authFail int32 // 不为0时鉴权失败
+	hang     int32 // 不为0时不完成握手
+	reject   int32 // 不为0时以该HTTP状态码拒绝握手
+	subError int32 // 不为0时行情订阅返回错误
+}
+
+// newTestServer 启动本地服务，调用方负责 close
+func newTestServer() *testServer {
+	ts := &testServer{}
+	ts.server = httptest.NewTLSServer(http.HandlerFunc(ts.handle))
+	return ts
+}
+
+func (ts *testServer) close() {
+	ts.server.Close()
+}
+
+func (ts *testServer) handle(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&ts.hang) != 0 {
+		<-r.Context().Done()
+		return
+	}
+	if status := atomic.LoadInt32(&ts.reject); status != 0 {
+		http.Error(w, "rejected", int(status))
+		return
+	}
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	atomic.AddInt32(&ts.accepts, 1)
+	ts.conns.Store(conn, true)
+	defer ts.conns.Delete(conn)
+	defer conn.Close()
+
+	var m sync.Mutex
+	v2 := strings.HasSuffix(r.URL.Path, "/v2")
+	send := func(message map[string]interface{}) {
+		b, _ := json.Marshal(message)
+		m.Lock()
+		defer m.Unlock()
+		if v2 {
+			conn.WriteMessage(websocket.TextMessage, b)
+		} else {
+			conn.WriteMessage(websocket.BinaryMessage, gzipBytes(b))
+		}
+	}
+	for {
+		_, b, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var message map[string]interface{}
+		json.Unmarshal(b, &message)
+		subscribing := message["sub"] != nil || message["op"] == "sub" || message["action"] == "sub"
+		if subscribing && atomic.AddInt32(&ts.killSub, -1) >= 0 {
+			return
+		}
+		if subscribing && atomic.LoadInt32(&ts.dropSub) != 0 {
+			continue
+		}
+		authFailed := atomic.LoadInt32(&ts.authFail) != 0
+		switch {
+		case message["sub"] != nil && atomic.LoadInt32(&ts.subError) != 0:
+			send(map[string]interface{}{"id": message["id"], "status": "error", "err-code": "bad-request", "err-msg": "invalid topic"})
+		case message["sub"] != nil:
+			send(map[string]interface{}{"id": message["id"], "status": "ok", "subbed": message["sub"]})
+		case message["ping"] != nil:
+			send(map[string]interface{}{"pong": message["ping"]})
+		case message["op"] == "auth":
+			code := 0
+			if authFailed {
+				code = 2002
+			}
+			send(map[string]interface{}{"op": "auth", "cid": message["cid"], "err-code": code})
+		case message["op"] == "req":
+			go send(map[string]interface{}{"op": "req", "topic": message["topic"], "cid": message["cid"], "err-code": 0, "data": message["cid"]})
+		case message["op"] == "sub":
+			send(map[string]interface{}{"op": "sub", "topic": message["topic"], "cid": message["cid"], "err-code": 0})
+		case message["action"] == "req":
+			code := 200
+			if authFailed {
+				code = 2002
+			}
+			send(map[string]interface{}{"action": "req", "ch": message["ch"], "code": code})
+		case message["action"] == "sub":
+			send(map[string]interface{}{"action": "sub", "ch": message["ch"], "code": 200})
+		}
+	}
+}
+
+// dropAll 断开所有连接
+func (ts *testServer) dropAll() {
+	ts.conns.Range(func(key, value interface{}) bool {
+		key.(*websocket.Conn).Close()
+		return true
+	})
+}
+
+// options 指向本地服务的配置项，默认快速重连
+func (ts *testServer) options(extra ...config.Option) []config.Option {
+	dialer := &websocket.Dialer{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, HandshakeTimeout: 5 * time.Second}
+	return append([]config.Option{
+		config.WithHost(strings.TrimPrefix(ts.server.URL, "https://")),
+		config.WithDialer(dialer),
+		config.WithHeartbeat(50 * time.Millisecond),
+		config.WithReconnect(&config.ReconnectBackoff{BaseDelay: 20 * time.Millisecond, MaxDelay: 50 * time.Millisecond}),
+	}, extra...)
+}
+
+func gzipBytes(b []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(b)
+	w.Close()
+	return buf.Bytes()
+}
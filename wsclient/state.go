@@ -0,0 +1,123 @@
+package wsclient
+
+import "sync"
+
+// ConnState WebSocket 连接状态
+type ConnState int
+
+const (
+	StateConnecting   ConnState = iota // 首次连接中
+	StateConnected                     // 已连接
+	StateDisconnected                  // 连接已断开，未在重连
+	StateReconnecting                  // 重连中
+	StateClosed                        // 已调用 Close
+)
+
+func (state ConnState) String() string {
+	switch state {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateDisconnected:
+		return "disconnected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// currentState 当前连接状态
+func (client *huobiWebSocket) currentState() ConnState {
+	client.m.RLock()
+	defer client.m.RUnlock()
+	return client.state
+}
+
+// setState 修改连接状态，Close 后状态不再变化
+func (client *huobiWebSocket) setState(state ConnState) {
+	client.m.Lock()
+	defer client.m.Unlock()
+	if client.state != StateClosed {
+		client.state = state
+	}
+}
+
+// hookQueue 按触发顺序在独立的goroutine中执行事件回调，
+// 回调中可以调用 Close、Reconnect 等方法而不会阻塞 Client 内部的goroutine
+type hookQueue struct {
+	events  []func()
+	running bool
+	m       sync.Mutex
+}
+
+// dispatch 将回调加入队列，队列空闲时启动goroutine依次执行
+func (queue *hookQueue) dispatch(fn func()) {
+	queue.m.Lock()
+	defer queue.m.Unlock()
+	queue.events = append(queue.events, fn)
+	if !queue.running {
+		queue.running = true
+		go queue.run()
+	}
+}
+
+func (queue *hookQueue) run() {
+	for {
+		queue.m.Lock()
+		if len(queue.events) == 0 {
+			queue.running = false
+			queue.m.Unlock()
+			return
+		}
+		fn := queue.events[0]
+		queue.events[0] = nil
+		queue.events = queue.events[1:]
+		queue.m.Unlock()
+		fn()
+	}
+}
+
+func (client *huobiWebSocket) onConnected() {
+	if fn := client.cfg.Hooks.OnConnected; fn != nil {
+		client.hooks.dispatch(fn)
+	}
+}
+
+func (client *huobiWebSocket) onDisconnected(err error) {
+	if fn := client.cfg.Hooks.OnDisconnected; fn != nil {
+		client.hooks.dispatch(func() { fn(err) })
+	}
+}
+
+func (client *huobiWebSocket) onReconnecting(attempt int) {
+	if fn := client.cfg.Hooks.OnReconnecting; fn != nil {
+		client.hooks.dispatch(func() { fn(attempt) })
+	}
+}
+
+func (client *huobiWebSocket) onReconnected() {
+	if fn := client.cfg.Hooks.OnReconnected; fn != nil {
+		client.hooks.dispatch(fn)
+	}
+}
+
+func (client *huobiWebSocket) onAuthFailed(err error) {
+	if fn := client.cfg.Hooks.OnAuthFailed; fn != nil {
+		client.hooks.dispatch(func() { fn(err) })
+	}
+}
+
+func (client *huobiWebSocket) onResubscribeFailed(topic string, err error) {
+	if fn := client.cfg.Hooks.OnResubscribeFailed; fn != nil {
+		client.hooks.dispatch(func() { fn(topic, err) })
+	}
+}
+
+func (client *huobiWebSocket) onReconnectGaveUp(err error) {
+	if fn := client.cfg.Hooks.OnReconnectGaveUp; fn != nil {
+		client.hooks.dispatch(func() { fn(err) })
+	}
+}
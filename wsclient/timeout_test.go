@@ -0,0 +1,75 @@
+package wsclient
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/feeeei/huobiapi-go/config"
+)
+
+func TestSubscribeTimeout(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	client, err := NewTradeWSV2Client("ak", "sk", ts.options(config.WithRequestTimeout(100*time.Millisecond))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	atomic.StoreInt32(&ts.dropSub, 1)
+	if err := client.Subscribe("orders#btcusdt", noop); !errors.Is(err, ErrRequestTimeout) {
+		t.Fatalf("err = %v, want ErrRequestTimeout", err)
+	}
+	if client.ws.listener("orders#btcusdt") != nil {
+		t.Error("listener registered after timeout")
+	}
+}
+
+func TestSubscribeContextCanceled(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	client, err := NewTradeWSClient("ak", "sk", ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	atomic.StoreInt32(&ts.dropSub, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.SubscribeContext(ctx, "orders.btcusdt", noop); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestPendingFailsOnDisconnect(t *testing.T) {
+	ts := newTestServer()
+	defer ts.close()
+	client, err := NewTradeWSV2Client("ak", "sk", ts.options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetAutoReconnect(false)
+	atomic.StoreInt32(&ts.dropSub, 1)
+
+	done := make(chan error, 1)
+	go func() { done <- client.Subscribe("orders#btcusdt", noop) }()
+	time.Sleep(50 * time.Millisecond)
+	ts.dropAll()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrConnectionClosed) {
+			t.Fatalf("err = %v, want ErrConnectionClosed", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("pending subscribe not failed on disconnect")
+	}
+
+	// 未开启自动重连时，断开后的请求直接失败
+	time.Sleep(50 * time.Millisecond)
+	if err := client.Subscribe("orders#ethusdt", noop); !errors.Is(err, ErrConnectionClosed) {
+		t.Fatalf("err = %v, want ErrConnectionClosed", err)
+	}
+}
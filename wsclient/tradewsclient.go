@@ -1,81 +1,94 @@
 package wsclient
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/bitly/go-simplejson"
+	"github.com/feeeei/huobiapi-go/apierror"
 	"github.com/feeeei/huobiapi-go/config"
-	"github.com/feeeei/huobiapi-go/debug"
 	"github.com/feeeei/huobiapi-go/sign"
 	"github.com/feeeei/huobiapi-go/utils"
 )
 
 type TradeWSClient struct {
-	ws            *huobiWebSocket
-	subscribeWait map[string]chan error
-	responseWait  map[string]chan *simplejson.Json
-	sign          *sign.Sign
-	autoReconnect bool
+	cfg  *config.Config
+	ws   *huobiWebSocket
+	sign *sign.Sign
 }
 
 // NewTradeWSClient WebSocket格式交易Client
-func NewTradeWSClient(accessKeyID, accessKeySecret string) (*TradeWSClient, error) {
+func NewTradeWSClient(accessKeyID, accessKeySecret string, options ...config.Option) (*TradeWSClient, error) {
+	cfg := config.New(options...)
 	client := &TradeWSClient{
-		subscribeWait: make(map[string]chan error),
-		responseWait:  make(map[string]chan *simplejson.Json),
-		sign:          sign.NewSign(accessKeyID, accessKeySecret, "2"),
-		autoReconnect: true,
+		cfg:  cfg,
+		sign: cfg.NewSign(accessKeyID, accessKeySecret, "2"),
 	}
+	client.ws = newHuobiWebSocket(cfg, cfg.WsTradeEndpoint, apierror.SourceWebSocketTrade, client, true)
 	if err := client.connect(); err != nil {
+		client.ws.close()
 		return nil, err
 	}
 	return client, nil
 }
 
 func (client *TradeWSClient) connect() error {
-	ws, err := newHuobiWebSocket(config.HuobiWsTradeEndpoint, client, client.autoReconnect, true)
-	if err != nil {
+	if err := client.ws.newConnect(); err != nil {
 		return err
 	}
-	client.ws = ws
 	if err := client.auth(); err != nil {
-		ws.close()
+		client.ws.onAuthFailed(err)
+		client.ws.closeConn(err)
 		return err
 	}
-	debug.Println("Trade websocket auth sccessful")
+	client.cfg.Logger.Println("Trade websocket auth sccessful")
+	client.ws.connected()
 	return nil
 }
 
 // auth 鉴权
 func (client *TradeWSClient) auth() error {
-	message := client.authParams()
-	client.ws.sendMessage(message)
-	client.subscribeWait["auth"] = make(chan error)
-	return <-client.subscribeWait["auth"]
+	message, err := client.authParams()
+	if err != nil {
+		return err
+	}
+	_, err = client.ws.roundTrip(context.Background(), pendingKey("auth", ""), "cid", message)
+	return err
 }
 
 // Request 一次性类请求，阻塞式返回结果
 func (client *TradeWSClient) Request(topic string, fields ...map[string]interface{}) (*simplejson.Json, error) {
-	var field map[string]interface{}
-	if fields == nil {
-		field = make(map[string]interface{})
-	} else {
-		field = fields[0]
+	return client.RequestContext(context.Background(), topic, fields...)
+}
+
+// RequestContext 一次性类请求，阻塞式返回结果，ctx 结束时停止等待
+func (client *TradeWSClient) RequestContext(ctx context.Context, topic string, fields ...map[string]interface{}) (*simplejson.Json, error) {
+	// 复制请求参数，避免并发请求共用同一 map
+	field := make(map[string]interface{})
+	if fields != nil {
+		for k, v := range fields[0] {
+			field[k] = v
+		}
 	}
 	field["topic"] = topic
 	field["op"] = "req"
-	client.ws.sendMessage(field)
-	client.responseWait[topic] = make(chan *simplejson.Json)
-	json := <-client.responseWait[topic]
+	json, err := client.ws.roundTrip(ctx, pendingKey("req", topic), "cid", field)
+	if err != nil {
+		return nil, err
+	}
 	return json, client.checkResponseError(json)
 }
 
 // HandleRequest 将Response解析到obj中
 func (client *TradeWSClient) HandleRequest(topic string, obj interface{}, fields ...map[string]interface{}) (*simplejson.Json, error) {
+	return client.HandleRequestContext(context.Background(), topic, obj, fields...)
+}
+
+// HandleRequestContext 可取消的HandleRequest
+func (client *TradeWSClient) HandleRequestContext(ctx context.Context, topic string, obj interface{}, fields ...map[string]interface{}) (*simplejson.Json, error) {
 	if err := utils.CheckPointer(obj); err != nil {
 		return nil, err
 	}
-	resp, err := client.Request(topic, fields...)
+	resp, err := client.RequestContext(ctx, topic, fields...)
 	if err != nil {
 		return resp, err
 	}
@@ -84,22 +97,33 @@ func (client *TradeWSClient) HandleRequest(topic string, obj interface{}, fields
 
 // Subscribe 订阅主题
 func (client *TradeWSClient) Subscribe(topic string, listener Subscriber) error {
+	return client.SubscribeContext(context.Background(), topic, listener)
+}
+
+// SubscribeContext 订阅主题，ctx 结束时停止等待订阅结果
+func (client *TradeWSClient) SubscribeContext(ctx context.Context, topic string, listener Subscriber) error {
+	if client.ws.isClosed() {
+		return ErrClientClosed
+	}
 	// 如果已经订阅，直接刷新 listener
-	if client.ws.subscribers[topic] != nil {
+	if client.ws.listener(topic) != nil {
 		client.ws.subscribe(topic, listener)
 		return nil
 	}
 
-	field := map[string]interface{}{"topic": topic, "op": "sub"}
-	client.ws.sendMessage(field)
-	client.subscribeWait[topic] = make(chan error)
-	if err := <-client.subscribeWait[topic]; err != nil {
+	if err := client.sendSubscribe(ctx, topic); err != nil {
 		return err
 	}
-	client.ws.subscribers[topic] = listener
+	client.ws.subscribe(topic, listener)
 	return nil
 }
 
+func (client *TradeWSClient) sendSubscribe(ctx context.Context, topic string) error {
+	field := map[string]interface{}{"topic": topic, "op": "sub"}
+	_, err := client.ws.roundTrip(ctx, pendingKey("sub", topic), "cid", field)
+	return err
+}
+
 // UnSubscribe 取消订阅主题
 func (client *TradeWSClient) UnSubscribe(topic string) {
 	client.ws.sendMessage(map[string]interface{}{"op": "unsub", "topic": topic})
@@ -108,15 +132,33 @@ func (client *TradeWSClient) UnSubscribe(topic string) {
 
 // SetAutoReconnect 设置socket中断时自动重新链接，默认true
 func (client *TradeWSClient) SetAutoReconnect(autoReconnect bool) {
-	client.autoReconnect = autoReconnect
-	client.ws.autoReconnect = autoReconnect
+	client.ws.setAutoReconnect(autoReconnect)
 }
 
-// Reconnect 重新连接，关闭旧链接，建立新链接，重新授权，重新订阅
+// Reconnect 重新连接，关闭旧链接，建立新链接，重新授权，重新订阅。
+// 已有重连在进行时直接返回；放弃自动重连后可调用以重新开始
 func (client *TradeWSClient) Reconnect() {
 	client.ws.reconnect()
 }
 
+// State 当前连接状态
+func (client *TradeWSClient) State() ConnState {
+	return client.ws.currentState()
+}
+
+// Close 取消所有订阅并关闭连接，停止自动重连，等待后台goroutine退出。
+// 之后的订阅及请求均返回 ErrClientClosed，不能在订阅回调中调用
+func (client *TradeWSClient) Close() error {
+	if client.ws.isClosed() {
+		return nil
+	}
+	for topic := range client.ws.topics() {
+		client.UnSubscribe(topic)
+	}
+	client.ws.close()
+	return nil
+}
+
 // handle 处理消息
 func (client *TradeWSClient) handle(json *simplejson.Json) {
 	op := json.Get("op").MustString()
@@ -128,46 +170,52 @@ func (client *TradeWSClient) handle(json *simplejson.Json) {
 	case "ping":
 		json.Set("op", "pong")
 		client.ws.sendMessage(json)
-	case "auth":
-		client.handleError("auth", json)
-	case "sub":
-		client.handleError(topic, json)
+	case "auth", "sub":
+		client.handleError(op, topic, json)
 	case "unsub":
-		debug.Println("Unsub", topic)
+		client.cfg.Logger.Println("Unsub", topic)
 	case "req":
 		client.handleResponse(topic, json)
 	case "notify":
-		subscriber := client.ws.subscribers[topic]
+		subscriber := client.ws.listener(topic)
 		if subscriber != nil {
 			subscriber(topic, json)
 		}
 	}
 }
 
-func (client *TradeWSClient) handleError(topic string, json *simplejson.Json) {
-	client.subscribeWait[topic] <- client.checkResponseError(json)
+func (client *TradeWSClient) handleError(op, topic string, json *simplejson.Json) {
+	cid := json.Get("cid").MustString()
+	client.ws.pending.resolve(cid, pendingKey(op, topic), json, client.checkResponseError(json))
 }
 
 func (client *TradeWSClient) handleResponse(topic string, json *simplejson.Json) {
-	client.responseWait[topic] <- json
+	cid := json.Get("cid").MustString()
+	client.ws.pending.resolve(cid, pendingKey("req", topic), json, nil)
 }
 
-func (client *TradeWSClient) authParams() map[string]interface{} {
+func (client *TradeWSClient) authParams() (map[string]interface{}, error) {
 	params := client.sign.GetSignFields()
-	params["Signature"] = utils.Sign("GET",
-		client.ws.url.Host,
-		client.ws.url.Path,
-		client.sign.AccessKeySecret,
-		params)
+	signature, err := client.sign.SignRequest("GET", client.ws.url.Host, client.ws.url.Path, params)
+	if err != nil {
+		return nil, err
+	}
+	params["Signature"] = signature
 	params["op"] = "auth"
-	return params
+	return params, nil
 }
 
 func (client *TradeWSClient) checkResponseError(json *simplejson.Json) error {
 	if json.Get("err-code").MustInt() == 0 {
 		return nil
 	}
-	return fmt.Errorf(json.Get("err-msg").MustString())
+	return &apierror.APIError{
+		Source:   apierror.SourceWebSocketTrade,
+		Code:     json.Get("err-code").MustInt(),
+		Message:  json.Get("err-msg").MustString(),
+		Endpoint: json.Get("topic").MustString(json.Get("op").MustString()),
+		Body:     encodeBody(json),
+	}
 }
 
 func getRequestFields(topic string) map[string]interface{} {
@@ -1,81 +1,93 @@
 package wsclient
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/bitly/go-simplejson"
+	"github.com/feeeei/huobiapi-go/apierror"
 	"github.com/feeeei/huobiapi-go/config"
-	"github.com/feeeei/huobiapi-go/debug"
 	"github.com/feeeei/huobiapi-go/sign"
-	"github.com/feeeei/huobiapi-go/utils"
 )
 
 type TradeWSV2Client struct {
-	ws            *huobiWebSocket
-	subscribeWait map[string]chan error
-	responseWait  map[string]chan *simplejson.Json
-	sign          *sign.Sign
-	autoReconnect bool
+	cfg  *config.Config
+	ws   *huobiWebSocket
+	sign *sign.Sign
 }
 
 // NewTradeWSV2Client WebSocket格式交易Client
-func NewTradeWSV2Client(accessKeyID, accessKeySecret string) (*TradeWSV2Client, error) {
+func NewTradeWSV2Client(accessKeyID, accessKeySecret string, options ...config.Option) (*TradeWSV2Client, error) {
+	cfg := config.New(options...)
 	client := &TradeWSV2Client{
-		subscribeWait: make(map[string]chan error),
-		responseWait:  make(map[string]chan *simplejson.Json),
-		sign:          sign.NewSign(accessKeyID, accessKeySecret, "2.1"),
-		autoReconnect: true,
+		cfg:  cfg,
+		sign: cfg.NewSign(accessKeyID, accessKeySecret, "2.1"),
 	}
+	client.ws = newHuobiWebSocket(cfg, cfg.WsTradeV2Endpoint, apierror.SourceWebSocketTradeV2, client, false)
 	if err := client.connect(); err != nil {
+		client.ws.close()
 		return nil, err
 	}
 	return client, nil
 }
 
 func (client *TradeWSV2Client) connect() error {
-	ws, err := newHuobiWebSocket(config.HuobiWsTradeV2Endpoint, client, client.autoReconnect, false)
-	if err != nil {
+	if err := client.ws.newConnect(); err != nil {
 		return err
 	}
-	client.ws = ws
 	if err := client.auth(); err != nil {
-		ws.close()
+		client.ws.onAuthFailed(err)
+		client.ws.closeConn(err)
 		return err
 	}
-	debug.Println("TradeV2 websocket auth sccessful")
+	client.cfg.Logger.Println("TradeV2 websocket auth sccessful")
+	client.ws.connected()
 	return nil
 }
 
 func (client *TradeWSV2Client) auth() error {
-	message := client.authParams()
+	message, err := client.authParams()
+	if err != nil {
+		return err
+	}
 	authMessage := map[string]interface{}{
 		"action": "req",
 		"ch":     "auth",
 		"params": message,
 	}
-	client.ws.sendMessage(authMessage)
-	client.subscribeWait["auth"] = make(chan error)
-	return <-client.subscribeWait["auth"]
+	_, err = client.ws.roundTrip(context.Background(), pendingKey("req", "auth"), "", authMessage)
+	return err
 }
 
 // Subscribe 订阅主题
 func (client *TradeWSV2Client) Subscribe(topic string, listener Subscriber) error {
+	return client.SubscribeContext(context.Background(), topic, listener)
+}
+
+// SubscribeContext 订阅主题，ctx 结束时停止等待订阅结果
+func (client *TradeWSV2Client) SubscribeContext(ctx context.Context, topic string, listener Subscriber) error {
+	if client.ws.isClosed() {
+		return ErrClientClosed
+	}
 	// 如果已经订阅，直接刷新 listener
-	if client.ws.subscribers[topic] != nil {
+	if client.ws.listener(topic) != nil {
 		client.ws.subscribe(topic, listener)
 		return nil
 	}
 
-	field := map[string]interface{}{"action": "sub", "ch": topic}
-	client.ws.sendMessage(field)
-	client.subscribeWait[topic] = make(chan error)
-	if err := <-client.subscribeWait[topic]; err != nil {
+	if err := client.sendSubscribe(ctx, topic); err != nil {
 		return err
 	}
-	client.ws.subscribers[topic] = listener
+	client.ws.subscribe(topic, listener)
 	return nil
 }
 
+func (client *TradeWSV2Client) sendSubscribe(ctx context.Context, topic string) error {
+	// v2 接口的响应不带请求ID，同一主题的请求按发送顺序匹配响应
+	field := map[string]interface{}{"action": "sub", "ch": topic}
+	_, err := client.ws.roundTrip(ctx, pendingKey("sub", topic), "", field)
+	return err
+}
+
 // UnSubscribe 取消订阅主题
 func (client *TradeWSV2Client) UnSubscribe(topic string) {
 	// TODO 火币暂未实现该接口，先本地取消订阅
@@ -84,15 +96,33 @@ func (client *TradeWSV2Client) UnSubscribe(topic string) {
 
 // SetAutoReconnect 设置socket中断时自动重新链接，默认true
 func (client *TradeWSV2Client) SetAutoReconnect(autoReconnect bool) {
-	client.autoReconnect = autoReconnect
-	client.ws.autoReconnect = autoReconnect
+	client.ws.setAutoReconnect(autoReconnect)
 }
 
-// Reconnect 重新连接，关闭旧链接，建立新链接，重新授权，重新订阅
+// Reconnect 重新连接，关闭旧链接，建立新链接，重新授权，重新订阅。
+// 已有重连在进行时直接返回；放弃自动重连后可调用以重新开始
 func (client *TradeWSV2Client) Reconnect() {
 	client.ws.reconnect()
 }
 
+// State 当前连接状态
+func (client *TradeWSV2Client) State() ConnState {
+	return client.ws.currentState()
+}
+
+// Close 取消所有订阅并关闭连接，停止自动重连，等待后台goroutine退出。
+// 之后的订阅均返回 ErrClientClosed，不能在订阅回调中调用
+func (client *TradeWSV2Client) Close() error {
+	if client.ws.isClosed() {
+		return nil
+	}
+	for topic := range client.ws.topics() {
+		client.UnSubscribe(topic)
+	}
+	client.ws.close()
+	return nil
+}
+
 // handle 处理消息
 func (client *TradeWSV2Client) handle(json *simplejson.Json) {
 	action := json.Get("action").MustString()
@@ -104,44 +134,40 @@ func (client *TradeWSV2Client) handle(json *simplejson.Json) {
 	case "ping":
 		json.Set("action", "pong")
 		client.ws.sendMessage(json)
-	case "req":
-		if ch == "auth" {
-			client.handleError(ch, json)
-		} else {
-			client.handleResponse(ch, json)
-		}
-	case "sub":
-		client.handleError(ch, json)
+	case "req", "sub":
+		client.handleError(action, ch, json)
 	case "push":
-		subscriber := client.ws.subscribers[ch]
+		subscriber := client.ws.listener(ch)
 		if subscriber != nil {
 			subscriber(ch, json)
 		}
 	}
 }
 
-func (client *TradeWSV2Client) handleResponse(topic string, json *simplejson.Json) {
-	client.responseWait[topic] <- json
+func (client *TradeWSV2Client) handleError(action, ch string, json *simplejson.Json) {
+	client.ws.pending.resolve("", pendingKey(action, ch), json, client.checkResponseError(json))
 }
 
-func (client *TradeWSV2Client) handleError(topic string, json *simplejson.Json) {
-	client.subscribeWait[topic] <- client.checkResponseError(json)
-}
-
-func (client *TradeWSV2Client) authParams() map[string]interface{} {
+func (client *TradeWSV2Client) authParams() (map[string]interface{}, error) {
 	params := client.sign.GetSignFields()
-	params["signature"] = utils.Sign("GET",
-		client.ws.url.Host,
-		client.ws.url.Path,
-		client.sign.AccessKeySecret,
-		params)
+	signature, err := client.sign.SignRequest("GET", client.ws.url.Host, client.ws.url.Path, params)
+	if err != nil {
+		return nil, err
+	}
+	params["signature"] = signature
 	params["authType"] = "api"
-	return params
+	return params, nil
 }
 
 func (client *TradeWSV2Client) checkResponseError(json *simplejson.Json) error {
 	if json.Get("code").MustInt() == 200 {
 		return nil
 	}
-	return fmt.Errorf(json.Get("message").MustString())
+	return &apierror.APIError{
+		Source:   apierror.SourceWebSocketTradeV2,
+		Code:     json.Get("code").MustInt(),
+		Message:  json.Get("message").MustString(),
+		Endpoint: json.Get("ch").MustString(),
+		Body:     encodeBody(json),
+	}
 }